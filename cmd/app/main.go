@@ -2,14 +2,18 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
 	"os"
 	"os/signal"
 	"strconv"
 	"syscall"
 
+	"github.com/StounhandJ/shorts_forward/internal/cache"
 	"github.com/StounhandJ/shorts_forward/internal/config"
 	downloadersService "github.com/StounhandJ/shorts_forward/internal/downloaders"
 	"github.com/StounhandJ/shorts_forward/internal/downloaders/instagram"
@@ -35,7 +39,7 @@ func main() {
 	//---------------//
 
 	//------ HTTP клиент для отправки запросов ------//
-	client := http.Client{}
+	var transport http.RoundTripper
 
 	if cfg.Application.ProxyURL != "" {
 		proxyURL, err := url.Parse(cfg.Application.ProxyURL)
@@ -43,10 +47,24 @@ func main() {
 			utils.Log.Panic(err)
 		}
 
-		client.Transport = &http.Transport{
+		transport = &http.Transport{
 			Proxy: http.ProxyURL(proxyURL), // прокси
 		}
 	}
+
+	// Лимиты запросов к хостам платформ задаются ниже вместе с цепочками загрузчиков
+	rateLimited := downloadersService.NewRateLimitedTransport(transport)
+	client := http.Client{Transport: rateLimited}
+
+	// Cookie страницы ролика TikTok нужны ссылкам его CDN: они есть у загрузчика web
+	// и у клиента, которым бот проверяет и скачивает файлы. Остальные провайдеры их не получают
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		utils.Log.Panic(err)
+	}
+
+	tiktokClient := client
+	tiktokClient.Jar = jar
 	//---------------//
 
 	//------ TELEGRAM бот ------//
@@ -71,12 +89,116 @@ func main() {
 		os.Exit(1)
 	}
 
-	youtubeDownloader := youtube.New(&client, cfg.Application.Domain)
-	handler := handlers.NewHandler([]downloadersService.IDownloader{
-		youtubeDownloader, // TODO ТГ не может обработать ссылки на CDN ютуба, можно через себя транслировать
-		instagram.New(&client),
-		tiktok.New(&client),
+	youtubeSigning := youtube.Signing{Secret: cfg.Application.ProxySecret, TTL: cfg.Application.ProxyLinkTTL}
+	if err := youtubeSigning.Validate(); err != nil {
+		utils.Log.Error(err)
+		os.Exit(1)
+	}
+
+	youtubeDownloader := youtube.New(&client, cfg.Application.Domain, youtubeSigning, youtube.Adaptive{
+		Enabled:   cfg.YouTubeAdaptive.Enabled,
+		MaxHeight: cfg.YouTubeAdaptive.MaxHeight,
+		MaxSize:   int64(cfg.YouTubeAdaptive.MaxSize) << 20,
 	})
+	registry := downloadersService.NewRegistry(&client)
+	breakers := downloadersService.NewBreakers()
+
+	instagramSessions, err := instagram.LoadSessions(cfg.InstagramSessions.Path)
+	if err != nil {
+		utils.Log.Errorf("сессии instagram: %s", err)
+		os.Exit(1)
+	}
+
+	utils.Log.Infof("Сессий instagram: %d", instagramSessions.Alive())
+
+	// Цепочки обработки запросов платформ собираются из конфига
+	pipelines := []struct {
+		platform  downloadersService.Platform
+		providers []downloadersService.Provider
+		config    config.Platform
+	}{
+		{youtube.Platform, []downloadersService.Provider{{Name: "player", Downloader: youtubeDownloader}}, cfg.Downloaders.YouTube},
+		{instagram.Platform, []downloadersService.Provider{{Name: "html", Downloader: instagram.New(&client, instagramSessions)}}, cfg.Downloaders.Instagram},
+		{tiktok.Platform, []downloadersService.Provider{
+			{Name: "tikwm", Downloader: tiktok.New(&client)},
+			{Name: "web", Downloader: tiktok.NewWeb(&tiktokClient)},
+		}, cfg.Downloaders.TikTok},
+	}
+
+	for _, p := range pipelines {
+		providers, err := downloadersService.SelectProviders(p.providers, p.config.Providers)
+		if err != nil {
+			utils.Log.Errorf("%s: %s", p.platform.Name, err)
+			os.Exit(1)
+		}
+
+		failover := breakers.Failover(p.platform.Name, providers, p.config.Breaker.Threshold, p.config.Breaker.Cooldown)
+
+		downloader, err := downloadersService.Pipeline(failover, p.config.Middlewares, downloadersService.MiddlewareOptions{
+			Platform:     p.platform.Name,
+			Timeout:      p.config.Timeout,
+			CacheTTL:     p.config.CacheTTL,
+			CacheSize:    p.config.CacheSize,
+			Retries:      p.config.Retries,
+			RetryBackoff: p.config.RetryBackoff,
+		})
+		if err != nil {
+			utils.Log.Error(err)
+			os.Exit(1)
+		}
+
+		registry.Register(p.platform, downloader)
+
+		if probeURL := p.config.Breaker.ProbeURL; probeURL != "" {
+			_, link, err := registry.Resolve(context.Background(), probeURL)
+			if err != nil {
+				utils.Log.Errorf("%s: ссылка для проверки провайдеров: %s", p.platform.Name, err)
+				os.Exit(1)
+			}
+
+			go failover.Probe(context.Background(), link.URL)
+		}
+
+		if limit := p.config.RateLimit; limit.Rate > 0 {
+			for _, host := range limit.Hosts {
+				rateLimited.Limit(host, downloadersService.NewRateLimiter(limit.Rate, limit.Burst, limit.Wait))
+			}
+		}
+	}
+
+	admins := make([]int64, 0, len(cfg.Application.AdminIDs))
+
+	for _, id := range cfg.Application.AdminIDs {
+		admin, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			utils.Log.Errorf("AdminIDs: %s", err)
+			os.Exit(1)
+		}
+
+		admins = append(admins, admin)
+	}
+
+	fileIDs, err := cache.NewFileIDs(cfg.FileIDCache.Backend, cfg.FileIDCache.Path, cfg.FileIDCache.Size)
+	if err != nil {
+		utils.Log.Error(err)
+		os.Exit(1)
+	}
+
+	handler := handlers.NewHandler(
+		registry,
+		downloadersService.NewSelector(&tiktokClient),
+		downloadersService.NewFetcher(&tiktokClient),
+		fileIDs,
+		handlers.StorageChat{
+			ChatID:    int64(cfg.StorageChat.ChatID),
+			Budget:    cfg.StorageChat.Budget,
+			MaxSize:   int64(cfg.StorageChat.MaxSize) << 20,
+			Platforms: cfg.StorageChat.Platforms,
+		},
+		cfg.Application.InlineTimeout,
+		breakers,
+		admins,
+	)
 	handler.SetupRoutes(bh)
 
 	user, err := bot.GetMe(context.Background())
@@ -85,6 +207,8 @@ func main() {
 		os.Exit(1)
 	}
 
+	botStopped := make(chan error, 1)
+
 	go func() {
 		fmt.Printf(
 			"TG БОТ ID=%d имя=%s username=@%s\n",
@@ -92,7 +216,7 @@ func main() {
 			user.FirstName,
 			user.Username,
 		)
-		utils.Log.Fatal(bh.Start())
+		botStopped <- bh.Start()
 	}()
 
 	go func() {
@@ -107,6 +231,40 @@ func main() {
 
 		utils.Log.Fatal(server.ListenAndServe(":" + strconv.Itoa(cfg.Application.Port)))
 	}()
+
+	// Метрики и состояние провайдеров не для всех: отдельный сервер, обычно только на localhost
+	if cfg.Application.AdminAddr != "" {
+		go func() {
+			fmt.Printf(
+				"Служебный сервер %s\n", cfg.Application.AdminAddr,
+			)
+
+			server := fasthttp.Server{
+				LogAllErrors: false,
+				Handler: func(ctx *fasthttp.RequestCtx) {
+					switch string(ctx.Path()) {
+					case "/metrics":
+						ctx.SetContentType("application/json")
+						ctx.SetBodyString(utils.Metrics.String())
+					case "/breakers":
+						body, err := json.Marshal(breakers.Status())
+						if err != nil {
+							ctx.Error(err.Error(), fasthttp.StatusInternalServerError)
+
+							return
+						}
+
+						ctx.SetContentType("application/json")
+						ctx.SetBody(body)
+					default:
+						ctx.Error("not found", fasthttp.StatusNotFound)
+					}
+				},
+			}
+
+			utils.Log.Fatal(server.ListenAndServe(cfg.Application.AdminAddr))
+		}()
+	}
 	//---------------//
 
 	//------ Ожидание заершения программы ------//
@@ -114,5 +272,26 @@ func main() {
 
 	cSignal := make(chan os.Signal, 2)
 	signal.Notify(cSignal, os.Interrupt, syscall.SIGTERM)
-	<-cSignal
+
+	exitCode := 0
+
+	select {
+	case <-cSignal:
+		// Обработчики успевают дописать кэш file_id до его закрытия
+		if err := bh.Stop(); err != nil {
+			utils.Log.Error(err)
+		}
+	case err := <-botStopped:
+		utils.Log.Errorf("TG бот остановился: %v", err)
+
+		exitCode = 1
+	}
+
+	if closer, ok := fileIDs.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			utils.Log.Error(err)
+		}
+	}
+
+	os.Exit(exitCode)
 }
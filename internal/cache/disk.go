@@ -0,0 +1,186 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+)
+
+type diskRecord[V any] struct {
+	Key   string `json:"key"`
+	Value V      `json:"value"`
+}
+
+// Disk LRU, которое переживает перезапуск: каждая запись дописывается строкой JSON в файл,
+// при открытии файл перечитывается и сжимается до актуальных записей.
+// Пока файл открыт, он сжимается, когда устаревших строк становится больше размера кэша
+type Disk[V any] struct {
+	*LRU[V]
+	mu   sync.Mutex
+	path string
+	file *os.File
+	// lines строк в файле, включая перезаписанные и вытесненные
+	lines int
+}
+
+func NewDisk[V any](path string, size int) (*Disk[V], error) {
+	lru := NewLRU[V](size, 0)
+
+	if err := load(path, lru); err != nil {
+		return nil, err
+	}
+
+	d := &Disk[V]{
+		LRU:  lru,
+		path: path,
+	}
+
+	if err := d.compact(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+func (d *Disk[V]) Set(key string, value V) {
+	d.LRU.Set(key, value)
+
+	line, err := json.Marshal(diskRecord[V]{Key: key, Value: value})
+	if err != nil {
+		utils.Log.Error(err)
+
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, err := d.file.Write(append(line, '\n')); err != nil {
+		utils.Log.Error(err)
+
+		return
+	}
+
+	d.lines++
+
+	if d.lines-d.Len() > d.size {
+		if err := d.compact(); err != nil {
+			utils.Log.Error(err)
+		}
+	}
+}
+
+func (d *Disk[V]) Close() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.file.Close()
+}
+
+// compact сжимает файл до актуальных записей и заново открывает его на дозапись.
+// Старый файл закрывается только после открытия нового: при ошибке запись продолжается в старый.
+// Вызывается при открытии и под d.mu
+func (d *Disk[V]) compact() error {
+	lines, err := compact(d.path, d.LRU)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(d.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open cache file %s: %w", d.path, err)
+	}
+
+	old := d.file
+	d.file = file
+	d.lines = lines
+
+	if old != nil {
+		if err := old.Close(); err != nil {
+			return fmt.Errorf("close cache file %s: %w", d.path, err)
+		}
+	}
+
+	return nil
+}
+
+func load[V any](path string, lru *LRU[V]) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("open cache file %s: %w", path, err)
+	}
+
+	defer func() {
+		if err := f.Close(); err != nil {
+			utils.Log.Error(err)
+		}
+	}()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+
+	for scanner.Scan() {
+		var record diskRecord[V]
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// Недописанная строка после падения - просто пропускаем
+			continue
+		}
+
+		lru.Set(record.Key, record.Value)
+	}
+
+	return scanner.Err()
+}
+
+// compact перезаписывает файл только актуальными записями, возвращает их число
+func compact[V any](path string, lru *LRU[V]) (int, error) {
+	tmpPath := path + ".tmp"
+
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return 0, fmt.Errorf("open cache file %s: %w", tmpPath, err)
+	}
+
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+
+	var (
+		encErr error
+		lines  int
+	)
+
+	lru.each(func(key string, value V) {
+		if encErr == nil {
+			encErr = enc.Encode(diskRecord[V]{Key: key, Value: value})
+			lines++
+		}
+	})
+
+	if encErr == nil {
+		encErr = w.Flush()
+	}
+
+	if err := f.Close(); encErr == nil {
+		encErr = err
+	}
+
+	if encErr == nil {
+		encErr = os.Rename(tmpPath, path)
+	}
+
+	if encErr != nil {
+		if err := os.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+			utils.Log.Error(err)
+		}
+
+		return 0, fmt.Errorf("compact cache file %s: %w", path, encErr)
+	}
+
+	return lines, nil
+}
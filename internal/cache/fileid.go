@@ -0,0 +1,34 @@
+package cache
+
+import "fmt"
+
+const (
+	BackendMemory = "memory"
+	BackendDisk   = "disk"
+)
+
+// SentMedia публикация, уже загруженная в ТГ: повторно отправляется по file_id без скачивания
+type SentMedia struct {
+	Title   string     `json:"title"`
+	Caption string     `json:"caption"`
+	Files   []SentFile `json:"files"`
+}
+
+type SentFile struct {
+	FileID string `json:"file_id"`
+	Photo  bool   `json:"photo,omitempty"`
+	// Rendition вариант файла, из которого получен file_id
+	Rendition string `json:"rendition,omitempty"`
+}
+
+// NewFileIDs хранилище file_id по каноническому url публикации
+func NewFileIDs(backend, path string, size int) (Store[SentMedia], error) {
+	switch backend {
+	case "", BackendMemory:
+		return NewLRU[SentMedia](size, 0), nil
+	case BackendDisk:
+		return NewDisk[SentMedia](path, size)
+	default:
+		return nil, fmt.Errorf("неизвестный backend кэша file_id: %s", backend)
+	}
+}
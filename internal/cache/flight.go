@@ -0,0 +1,84 @@
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+// Flight схлопывает одновременные вычисления одного ключа в одно.
+// Вычисление не привязано к контексту того, кто его начал: если первый вызов отменён,
+// остальные дожидаются результата. Вычисление отменяется, только когда его больше никто не ждёт
+type Flight[V any] struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall[V]
+}
+
+type flightCall[V any] struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	value   V
+	err     error
+}
+
+// Do вызывает fn один раз на все одновременные вызовы с ключом key и ждёт результата,
+// пока не отменён ctx. fn получает контекст со значениями ctx, но без его отмены и дедлайна.
+// shared - результат получен вызовом, начатым раньше
+func (f *Flight[V]) Do(ctx context.Context, key string, fn func(ctx context.Context) (V, error)) (value V, shared bool, err error) {
+	f.mu.Lock()
+
+	if f.calls == nil {
+		f.calls = make(map[string]*flightCall[V])
+	}
+
+	c, shared := f.calls[key]
+	if !shared {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &flightCall[V]{done: make(chan struct{}), cancel: cancel}
+		f.calls[key] = c
+
+		go f.run(callCtx, key, c, fn)
+	}
+
+	c.waiters++
+	f.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.value, shared, c.err
+	case <-ctx.Done():
+		f.leave(key, c)
+
+		return value, shared, ctx.Err()
+	}
+}
+
+func (f *Flight[V]) run(ctx context.Context, key string, c *flightCall[V], fn func(ctx context.Context) (V, error)) {
+	defer c.cancel()
+
+	c.value, c.err = fn(ctx)
+
+	f.mu.Lock()
+	if f.calls[key] == c {
+		delete(f.calls, key)
+	}
+	f.mu.Unlock()
+	close(c.done)
+}
+
+// leave вызов перестал ждать результата. Последний ушедший отменяет вычисление,
+// а следующий вызов с тем же ключом начнёт новое
+func (f *Flight[V]) leave(key string, c *flightCall[V]) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if c.waiters--; c.waiters > 0 {
+		return
+	}
+
+	c.cancel()
+
+	if f.calls[key] == c {
+		delete(f.calls, key)
+	}
+}
@@ -1,13 +1,91 @@
 package config
 
+import "time"
+
 type Config struct {
 	Application Application `yaml:"Application" env:"APP" flag:""`
+	Downloaders Downloaders `yaml:"Downloaders" env:"DOWNLOADERS" flag:"downloaders"`
+	FileIDCache FileIDCache `yaml:"FileIDCache" env:"FILE_ID_CACHE" flag:"file-id-cache"`
+	StorageChat StorageChat `yaml:"StorageChat" env:"STORAGE_CHAT" flag:"storage-chat"`
+	// InstagramSessions аккаунты для историй и публикаций, закрытых стеной входа
+	InstagramSessions InstagramSessions `yaml:"InstagramSessions" env:"INSTAGRAM_SESSIONS" flag:"instagram-sessions"`
+	// YouTubeAdaptive ролики в 720p/1080p, склеенные из отдельных потоков видео и звука
+	YouTubeAdaptive YouTubeAdaptive `yaml:"YouTubeAdaptive" env:"YOUTUBE_ADAPTIVE" flag:"youtube-adaptive"`
 }
 
 type Application struct {
-	LogLevel   string `yaml:"LogLevel" env:"LOGLEVEL"`
-	TGBotToken string `yaml:"TGBotToken" env:"TG_BOT_TOKEN" flag:"tg-bot-token" usage:"Токен телегам бота"`
-	Port       int    `yaml:"Port" env:"PORT" flag:"port" usage:"Порт запуска api прокси"`
-	Domain      string `yaml:"Domain" env:"DOMAIN" flag:"domain" usage:"Домен к которому будет обращаться Телеглрам для прокси запроса Ютуб видео"`
-	ProxyURL   string `yaml:"ProxyURL" env:"PROXY_URL" flag:"proxy-url" cli:"optional" usage:"Прокси для отправки запросов"`
+	LogLevel      string        `yaml:"LogLevel" env:"LOGLEVEL"`
+	TGBotToken    string        `yaml:"TGBotToken" env:"TG_BOT_TOKEN" flag:"tg-bot-token" usage:"Токен телегам бота"`
+	Port          int           `yaml:"Port" env:"PORT" flag:"port" usage:"Порт запуска api прокси"`
+	Domain        string        `yaml:"Domain" env:"DOMAIN" flag:"domain" usage:"Домен к которому будет обращаться Телеглрам для прокси запроса Ютуб видео"`
+	ProxyURL      string        `yaml:"ProxyURL" env:"PROXY_URL" flag:"proxy-url" cli:"optional" usage:"Прокси для отправки запросов"`
+	AdminIDs      []string      `yaml:"AdminIDs" env:"ADMIN_IDS" flag:"admin-ids" cli:"optional" usage:"ID пользователей ТГ с доступом к служебным командам"`
+	InlineTimeout time.Duration `yaml:"InlineTimeout" env:"INLINE_TIMEOUT" flag:"inline-timeout" cli:"optional" usage:"Максимальное время подготовки inline ответа (ТГ ждёт ~10с)"`
+	ProxySecret   string        `yaml:"ProxySecret" env:"PROXY_SECRET" flag:"proxy-secret" usage:"Секрет для подписи ссылок на прокси Ютуб видео"`
+	ProxyLinkTTL  time.Duration `yaml:"ProxyLinkTTL" env:"PROXY_LINK_TTL" flag:"proxy-link-ttl" cli:"optional" usage:"Время жизни ссылки на прокси, должно быть больше CacheTTL Ютуба"`
+	// AdminAddr служебный http сервер отдельно от публичного порта прокси
+	AdminAddr string `yaml:"AdminAddr" env:"ADMIN_ADDR" flag:"admin-addr" cli:"optional" usage:"Адрес служебного сервера /metrics и /breakers, например 127.0.0.1:9090 (пусто - выключен)"`
+}
+
+// Downloaders настройки загрузчиков по платформам
+type Downloaders struct {
+	YouTube   Platform `yaml:"YouTube" env:"YOUTUBE" flag:"youtube"`
+	Instagram Platform `yaml:"Instagram" env:"INSTAGRAM" flag:"instagram"`
+	TikTok    Platform `yaml:"TikTok" env:"TIKTOK" flag:"tiktok"`
+}
+
+type Platform struct {
+	Timeout   time.Duration `yaml:"Timeout" env:"TIMEOUT" flag:"timeout" cli:"optional" usage:"Таймаут получения данных о ролике"`
+	CacheTTL  time.Duration `yaml:"CacheTTL" env:"CACHE_TTL" flag:"cache-ttl" cli:"optional" usage:"Время жизни кэша данных о ролике (0 - без кэша)"`
+	CacheSize int           `yaml:"CacheSize" env:"CACHE_SIZE" flag:"cache-size" cli:"optional" usage:"Количество роликов в кэше"`
+	// Middlewares цепочка обработки запросов, первый - внешний
+	Middlewares  []string      `yaml:"Middlewares" env:"MIDDLEWARES" flag:"middlewares" cli:"optional" usage:"Цепочка middleware: logging, cache, metrics, retry, timeout"`
+	Retries      int           `yaml:"Retries" env:"RETRIES" flag:"retries" cli:"optional" usage:"Количество повторов при временных ошибках"`
+	RetryBackoff time.Duration `yaml:"RetryBackoff" env:"RETRY_BACKOFF" flag:"retry-backoff" cli:"optional" usage:"Начальная задержка перед повтором"`
+	RateLimit    RateLimit     `yaml:"RateLimit" env:"RATE_LIMIT" flag:"rate-limit"`
+	// Providers источники данных в порядке приоритета, следующий используется при отказе предыдущего
+	Providers []string `yaml:"Providers" env:"PROVIDERS" flag:"providers" cli:"optional" usage:"Провайдеры в порядке приоритета"`
+	Breaker   Breaker  `yaml:"Breaker" env:"BREAKER" flag:"breaker"`
+}
+
+// Breaker предохранитель провайдера
+type Breaker struct {
+	Threshold int           `yaml:"Threshold" env:"THRESHOLD" flag:"threshold" cli:"optional" usage:"Неудач подряд до отключения провайдера"`
+	Cooldown  time.Duration `yaml:"Cooldown" env:"COOLDOWN" flag:"cooldown" cli:"optional" usage:"Через сколько отключённый провайдер проверяется снова"`
+	ProbeURL  string        `yaml:"ProbeURL" env:"PROBE_URL" flag:"probe-url" cli:"optional" usage:"Ссылка для фоновой проверки отключённого провайдера"`
+}
+
+// RateLimit ограничение частоты запросов к хостам, через которые платформа получает данные
+type RateLimit struct {
+	Rate  float64       `yaml:"Rate" env:"RATE" flag:"rate" cli:"optional" usage:"Запросов в секунду (0 - без ограничения)"`
+	Burst int           `yaml:"Burst" env:"BURST" flag:"burst" cli:"optional" usage:"Запросов подряд без ожидания"`
+	Wait  time.Duration `yaml:"Wait" env:"WAIT" flag:"wait" cli:"optional" usage:"Максимальное ожидание в очереди"`
+	Hosts []string      `yaml:"Hosts" env:"HOSTS" flag:"hosts" cli:"optional" usage:"Хосты платформы"`
+}
+
+// FileIDCache кэш file_id уже загруженных в ТГ публикаций
+type FileIDCache struct {
+	Backend string `yaml:"Backend" env:"BACKEND" flag:"backend" cli:"optional" usage:"memory (по умолчанию) или disk"`
+	Size    int    `yaml:"Size" env:"SIZE" flag:"size" cli:"optional" usage:"Количество публикаций в кэше"`
+	Path    string `yaml:"Path" env:"PATH" flag:"path" cli:"optional" usage:"Файл кэша для backend disk"`
+}
+
+// StorageChat приватный чат, куда бот загружает файлы, чтобы отвечать на inline запросы по file_id
+type StorageChat struct {
+	ChatID    int           `yaml:"ChatID" env:"ID" flag:"id" cli:"optional" usage:"ID приватного чата или канала (0 - выключено)"`
+	Budget    time.Duration `yaml:"Budget" env:"BUDGET" flag:"budget" cli:"optional" usage:"Сколько времени inline запроса можно потратить на загрузку"`
+	MaxSize   int           `yaml:"MaxSize" env:"MAX_SIZE" flag:"max-size" cli:"optional" usage:"Максимальный размер загружаемого файла в МБ"`
+	Platforms []string      `yaml:"Platforms" env:"PLATFORMS" flag:"platforms" cli:"optional" usage:"Платформы, для которых используется (пусто - все)"`
+}
+
+// InstagramSessions файл с cookie аккаунтов Instagram, по одной строке "sessionid=...; ds_user_id=...; csrftoken=..." на аккаунт
+type InstagramSessions struct {
+	Path string `yaml:"Path" env:"PATH" flag:"path" cli:"optional" usage:"Файл с cookie сессий Instagram (пусто - без сессий)"`
+}
+
+// YouTubeAdaptive склейка адаптивных потоков YouTube в MP4 на прокси
+type YouTubeAdaptive struct {
+	Enabled   bool `yaml:"Enabled" env:"ENABLED" flag:"enabled" cli:"optional" usage:"Отдавать ролики в высоком качестве, склеивая видео и звук"`
+	MaxHeight int  `yaml:"MaxHeight" env:"MAX_HEIGHT" flag:"max-height" cli:"optional" usage:"Наибольшее качество: 720, 1080 (0 - без ограничения)"`
+	MaxSize   int  `yaml:"MaxSize" env:"MAX_SIZE" flag:"max-size" cli:"optional" usage:"Максимальный размер склеиваемого ролика в МБ"`
 }
@@ -0,0 +1,198 @@
+package downloaders
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+)
+
+const (
+	defaultBreakerThreshold = 5
+	defaultBreakerCooldown  = time.Minute
+)
+
+// BreakerState состояние предохранителя провайдера
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // провайдер работает
+	BreakerOpen                         // провайдер отключён до пробного запроса
+	BreakerHalfOpen                     // идёт пробный запрос
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerStatus снимок состояния предохранителя для админки и api
+type BreakerStatus struct {
+	Name      string    `json:"name"`
+	State     string    `json:"state"`
+	Failures  int       `json:"failures"`
+	OpenedAt  time.Time `json:"opened_at,omitzero"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// Breaker предохранитель провайдера: после threshold неудач подряд запросы к провайдеру
+// не отправляются cooldown, затем пропускается один пробный запрос.
+// Ошибки конкретной публикации (не найдена, закрыта и т.п.) неудачей провайдера не считаются
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	lastErr  error
+}
+
+func NewBreaker(name string, threshold int, cooldown time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = defaultBreakerThreshold
+	}
+
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+
+	return &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+// Allow можно ли отправить запрос. После каждого разрешённого запроса нужно вызвать Done
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerClosed:
+		return true
+	case BreakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+
+		b.state = BreakerHalfOpen
+
+		return true
+	default:
+		return false
+	}
+}
+
+// Done учитывает результат запроса
+func (b *Breaker) Done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// Запрос отменён пользователем, о провайдере ничего не известно
+		if b.state == BreakerHalfOpen {
+			b.state = BreakerOpen
+		}
+	case !isProviderFailure(err):
+		if b.state != BreakerClosed {
+			utils.Log.Infof("%s: провайдер снова работает", b.name)
+		}
+
+		b.state = BreakerClosed
+		b.failures = 0
+	default:
+		b.failures++
+		b.lastErr = err
+
+		if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+			if b.state == BreakerClosed {
+				utils.Log.Warnf("%s: провайдер отключён после %d неудач подряд: %s", b.name, b.failures, err)
+				utils.Metrics.Add("breaker_open_"+b.name, 1)
+			}
+
+			b.state = BreakerOpen
+			b.openedAt = time.Now()
+		}
+	}
+}
+
+// State текущее состояние
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
+
+func (b *Breaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{
+		Name:     b.name,
+		State:    b.state.String(),
+		Failures: b.failures,
+	}
+
+	if b.state != BreakerClosed {
+		status.OpenedAt = b.openedAt
+	}
+
+	if b.lastErr != nil {
+		status.LastError = b.lastErr.Error()
+	}
+
+	return status
+}
+
+// isProviderFailure ошибка говорит о неработающем провайдере, а не о конкретной публикации
+func isProviderFailure(err error) bool {
+	return err != nil && !IsContentError(err)
+}
+
+// Breakers все предохранители бота, для просмотра состояния
+type Breakers struct {
+	mu   sync.Mutex
+	list []*Breaker
+}
+
+func NewBreakers() *Breakers {
+	return &Breakers{}
+}
+
+// Add создаёт предохранитель и запоминает его
+func (s *Breakers) Add(name string, threshold int, cooldown time.Duration) *Breaker {
+	b := NewBreaker(name, threshold, cooldown)
+
+	s.mu.Lock()
+	s.list = append(s.list, b)
+	s.mu.Unlock()
+
+	return b
+}
+
+// Status состояния в порядке создания
+func (s *Breakers) Status() []BreakerStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]BreakerStatus, 0, len(s.list))
+	for _, b := range s.list {
+		statuses = append(statuses, b.Status())
+	}
+
+	return statuses
+}
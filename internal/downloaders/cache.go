@@ -0,0 +1,67 @@
+package downloaders
+
+import (
+	"context"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/cache"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+)
+
+const defaultCacheSize = 1000
+
+// cachedDownloader кэширует данные о публикациях по каноническому url
+// и схлопывает одновременные запросы одной ссылки в один запрос к платформе
+type cachedDownloader struct {
+	IDownloader
+	platform string
+	cache    *cache.LRU[*Media]
+	inflight cache.Flight[*Media]
+}
+
+// Cache кэш на size публикаций со временем жизни ttl.
+// При ttl <= 0 загрузчик не оборачивается
+func Cache(platform string, size int, ttl time.Duration) Middleware {
+	return func(next IDownloader) IDownloader {
+		if ttl <= 0 {
+			return next
+		}
+
+		if size <= 0 {
+			size = defaultCacheSize
+		}
+
+		return &cachedDownloader{
+			IDownloader: next,
+			platform:    platform,
+			cache:       cache.NewLRU[*Media](size, ttl),
+		}
+	}
+}
+
+// Download общий запрос к платформе не зависит от отмены того, кто его начал: пока ответа ждёт
+// хотя бы один запрос той же ссылки, он продолжается. Время самого запроса ограничивает middleware timeout
+func (d *cachedDownloader) Download(ctx context.Context, url string) (*Media, error) {
+	if media, ok := d.cache.Get(url); ok {
+		utils.Metrics.Add("metadata_hit_"+d.platform, 1)
+
+		return media, nil
+	}
+
+	media, shared, err := d.inflight.Do(ctx, url, func(ctx context.Context) (*Media, error) {
+		utils.Metrics.Add("metadata_miss_"+d.platform, 1)
+
+		// Ошибки не кэшируются, следующий запрос снова пойдёт на платформу
+		media, err := d.IDownloader.Download(ctx, url)
+		if err == nil {
+			d.cache.Set(url, media)
+		}
+
+		return media, err
+	})
+	if shared {
+		utils.Metrics.Add("metadata_shared_"+d.platform, 1)
+	}
+
+	return media, err
+}
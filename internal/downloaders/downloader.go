@@ -1,32 +1,87 @@
 package downloaders
 
 import (
+	"context"
+	"time"
+
 	"github.com/StounhandJ/shorts_forward/internal/utils"
 )
 
 type IDownloader interface {
-	Download(url string) (*Video, error)
-	Valid(url string) bool
+	Download(ctx context.Context, url string) (*Media, error)
 }
 
-type Video struct {
+type MediaType string
+
+const (
+	MediaTypeVideo MediaType = "video"
+	MediaTypePhoto MediaType = "photo"
+)
+
+// Media данные о публикации: один ролик или альбом (слайдшоу, карусель)
+type Media struct {
 	Title        string
-	VideoURL     string
-	ThumbnailURL string
-	MimeType     string
-	Duration     int
 	ViewCount    int
 	LikeCount    int
+	CommentCount int
+	ShareCount   int
+	UploadedAt   time.Time
+	Author       Author
+	Music        Music
+	Items        []Item
+}
+
+// Item отдельное фото или видео публикации со всеми доступными вариантами файла
+type Item struct {
+	Type         MediaType
+	ThumbnailURL string
+	Duration     int
+	Renditions   []Rendition
+}
+
+// Rendition один из вариантов файла ролика
+type Rendition struct {
+	URL       string
+	MimeType  string
+	Width     int
+	Height    int
+	Bitrate   int   // бит/с, 0 если неизвестен
+	Size      int64 // байт, 0 если неизвестен
+	Codec     string
+	HasAudio  bool
+	Watermark bool
+	// Cookies ссылка работает только с cookie, полученными загрузчиком: ТГ по ней файл не скачает
+	Cookies bool
+}
+
+type Author struct {
+	ID        string
+	Username  string
+	Name      string
+	AvatarURL string
+}
+
+type Music struct {
+	Title    string
+	Author   string
+	URL      string
+	Duration int
+	Original bool
+}
+
+// IsAlbum публикация состоит из нескольких фото или видео
+func (m Media) IsAlbum() bool {
+	return len(m.Items) > 1
 }
 
-func (v Video) MainInfo() string {
+func (m Media) MainInfo() string {
 	var result string
-	if v.ViewCount != 0 {
-		result = utils.FormatBigInt(v.ViewCount) + "👁️ "
+	if m.ViewCount != 0 {
+		result = utils.FormatBigInt(m.ViewCount) + "👁️ "
 	}
 
-	if v.LikeCount != 0 {
-		result += utils.FormatBigInt(v.LikeCount) + "🤍"
+	if m.LikeCount != 0 {
+		result += utils.FormatBigInt(m.LikeCount) + "🤍"
 	}
 
 	return result
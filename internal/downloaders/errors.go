@@ -0,0 +1,71 @@
+package downloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Категории ошибок загрузки. Загрузчики оборачивают ими свои ошибки (fmt.Errorf("%w: ...")),
+// обработчики по ним выбирают объяснение для пользователя
+var (
+	ErrNotFound           = errors.New("публикация не найдена")
+	ErrPrivate            = errors.New("публикация закрыта или требует входа")
+	ErrGeoBlocked         = errors.New("публикация недоступна в регионе")
+	ErrAgeRestricted      = errors.New("публикация с возрастным ограничением")
+	ErrTooLarge           = errors.New("файл слишком большой")
+	ErrRateLimited        = errors.New("платформа ограничила число запросов")
+	ErrUnsupportedContent = errors.New("тип публикации не поддерживается")
+	ErrTemporary          = errors.New("временная ошибка")
+)
+
+// StatusError ошибка по http статусу ответа платформы
+func StatusError(code int) error {
+	switch {
+	case code == http.StatusNotFound, code == http.StatusGone:
+		return fmt.Errorf("%w: http %d", ErrNotFound, code)
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return fmt.Errorf("%w: http %d", ErrPrivate, code)
+	case code == http.StatusUnavailableForLegalReasons:
+		return fmt.Errorf("%w: http %d", ErrGeoBlocked, code)
+	case code == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: http %d", ErrRateLimited, code)
+	case code >= http.StatusInternalServerError:
+		return fmt.Errorf("%w: http %d", ErrTemporary, code)
+	default:
+		return fmt.Errorf("неожиданный http статус %d", code)
+	}
+}
+
+// Temporary помечает сетевую ошибку временной
+func Temporary(err error) error {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w", ErrTemporary, err)
+}
+
+// IsContentError ошибка относится к самой публикации, а не к работе платформы или загрузчика
+func IsContentError(err error) bool {
+	for _, contentErr := range []error{ErrNotFound, ErrPrivate, ErrGeoBlocked, ErrAgeRestricted, ErrUnsupportedContent, ErrTooLarge} {
+		if errors.Is(err, contentErr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTemporary есть смысл повторить запрос позже
+func IsTemporary(err error) bool {
+	if errors.Is(err, ErrTemporary) || errors.Is(err, ErrRateLimited) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,128 @@
+package downloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+)
+
+// probeTimeout время на пробный запрос к отключённому провайдеру
+const probeTimeout = 15 * time.Second
+
+// Provider источник данных платформы, например tikwm для TikTok
+type Provider struct {
+	Name       string
+	Downloader IDownloader
+}
+
+// SelectProviders выбирает провайдеров по именам из конфига в заданном порядке.
+// Пустой список - все доступные в исходном порядке
+func SelectProviders(available []Provider, names []string) ([]Provider, error) {
+	if len(names) == 0 {
+		return available, nil
+	}
+
+	selected := make([]Provider, 0, len(names))
+
+	for _, name := range names {
+		i := slices.IndexFunc(available, func(p Provider) bool { return p.Name == name })
+		if i == -1 {
+			return nil, fmt.Errorf("неизвестный провайдер %q", name)
+		}
+
+		selected = append(selected, available[i])
+	}
+
+	return selected, nil
+}
+
+// Failover перебирает провайдеров платформы по приоритету, пропуская отключённые предохранителем
+type Failover struct {
+	platform  string
+	providers []Provider
+	breakers  []*Breaker
+	cooldown  time.Duration
+}
+
+// Failover собирает провайдеров платформы, у каждого свой предохранитель
+func (s *Breakers) Failover(platform string, providers []Provider, threshold int, cooldown time.Duration) *Failover {
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+
+	f := &Failover{
+		platform:  platform,
+		providers: providers,
+		cooldown:  cooldown,
+	}
+
+	for _, p := range providers {
+		f.breakers = append(f.breakers, s.Add(platform+"/"+p.Name, threshold, cooldown))
+	}
+
+	return f
+}
+
+func (f *Failover) Download(ctx context.Context, url string) (*Media, error) {
+	var errs []error
+
+	for i, p := range f.providers {
+		breaker := f.breakers[i]
+		if !breaker.Allow() {
+			continue
+		}
+
+		media, err := p.Downloader.Download(ctx, url)
+		breaker.Done(err)
+
+		// Ошибка самой публикации у другого провайдера будет такой же
+		if !isProviderFailure(err) || ctx.Err() != nil {
+			return media, err
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+
+		if i < len(f.providers)-1 {
+			utils.Metrics.Add("failover_"+f.platform, 1)
+			utils.Log.Warnf("%s: провайдер %s не ответил, пробуем следующего: %s", f.platform, p.Name, err)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("%s: %w: все провайдеры отключены", f.platform, ErrTemporary)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// Probe раз в cooldown проверяет отключённых провайдеров запросом url, пока ctx не завершится.
+// Без пробы провайдер проверяется первым пользовательским запросом после cooldown
+func (f *Failover) Probe(ctx context.Context, url string) {
+	ticker := time.NewTicker(f.cooldown)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for i, p := range f.providers {
+			breaker := f.breakers[i]
+			if breaker.State() != BreakerOpen || !breaker.Allow() {
+				continue
+			}
+
+			probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+			_, err := p.Downloader.Download(probeCtx, url)
+
+			cancel()
+			breaker.Done(err)
+		}
+	}
+}
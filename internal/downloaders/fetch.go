@@ -0,0 +1,82 @@
+package downloaders
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+)
+
+// Fetcher скачивает файл через http клиент бота (с прокси), когда ТГ не может забрать его по ссылке сам
+type Fetcher struct {
+	client *http.Client
+}
+
+func NewFetcher(client *http.Client) *Fetcher {
+	return &Fetcher{
+		client: client,
+	}
+}
+
+// Open открывает поток файла. Если файл больше limit, возвращается ErrTooLarge
+// (сразу по Content-Length или при чтении, если размер заранее неизвестен)
+func (f *Fetcher) Open(ctx context.Context, url string, limit int64) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 YaBrowser/25.10.0.0 Safari/537.36")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, Temporary(err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		closeBody(resp.Body)
+
+		return nil, fmt.Errorf("скачивание файла: %w", StatusError(resp.StatusCode))
+	}
+
+	if limit > 0 && resp.ContentLength > limit {
+		closeBody(resp.Body)
+
+		return nil, ErrTooLarge
+	}
+
+	return &limitedReadCloser{
+		rc:    resp.Body,
+		limit: limit,
+	}, nil
+}
+
+func closeBody(body io.Closer) {
+	if err := body.Close(); err != nil {
+		utils.Log.Error(err)
+	}
+}
+
+// limitedReadCloser возвращает ErrTooLarge, если прочитано больше limit байт
+type limitedReadCloser struct {
+	rc    io.ReadCloser
+	limit int64
+	read  int64
+}
+
+func (l *limitedReadCloser) Read(p []byte) (int, error) {
+	n, err := l.rc.Read(p)
+	l.read += int64(n)
+
+	if l.limit > 0 && l.read > l.limit {
+		return n, ErrTooLarge
+	}
+
+	return n, err
+}
+
+func (l *limitedReadCloser) Close() error {
+	return l.rc.Close()
+}
@@ -0,0 +1,169 @@
+//go:generate easyjson api.go
+package instagram
+
+import (
+	"context"
+	"fmt"
+	netUrl "net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/mailru/easyjson"
+)
+
+const (
+	apiURL = "https://www.instagram.com/api/v1"
+	// shortcodeAlphabet base64 алфавит, которым ID публикации кодируется в shortcode
+	shortcodeAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+)
+
+// easyjson:json
+type apiProfile struct {
+	Data struct {
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	} `json:"data"`
+}
+
+// easyjson:json
+type apiReels struct {
+	Reels map[string]struct {
+		Items []videoData `json:"items"`
+	} `json:"reels"`
+}
+
+// fromAPI данные публикации из api с авторизацией, последняя стратегия, чтобы не тратить сессии
+func (d downloader) fromAPI(ctx context.Context, _, shortcode string) (*downloaders.Media, error) {
+	if d.sessions.Alive() == 0 {
+		return nil, errNoSessions
+	}
+
+	id, err := mediaID(shortcode)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := d.authorized(ctx, apiURL+"/media/"+id+"/info/")
+	if err != nil {
+		return nil, err
+	}
+
+	var obj videoObject
+	if err := easyjson.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("api: %w", err)
+	}
+
+	if len(obj.Items) == 0 {
+		return nil, downloaders.ErrNotFound
+	}
+
+	return postMedia(obj.Items[0])
+}
+
+// story отдельная история пользователя, истории живут сутки
+func (d downloader) story(ctx context.Context, username, storyID string) (*downloaders.Media, error) {
+	data, err := d.authorized(ctx, apiURL+"/users/web_profile_info/?username="+netUrl.QueryEscape(username))
+	if err != nil {
+		return nil, err
+	}
+
+	var profile apiProfile
+	if err := easyjson.Unmarshal(data, &profile); err != nil {
+		return nil, fmt.Errorf("api: %w", err)
+	}
+
+	if profile.Data.User.ID == "" {
+		return nil, downloaders.ErrNotFound
+	}
+
+	items, err := d.reels(ctx, profile.Data.User.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		if item.ID == storyID || strings.HasPrefix(item.ID, storyID+"_") {
+			return storyMedia([]videoData{item}, "История @"+username)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: история истекла или удалена", downloaders.ErrNotFound)
+}
+
+// highlight все истории из актуального
+func (d downloader) highlight(ctx context.Context, highlightID string) (*downloaders.Media, error) {
+	items, err := d.reels(ctx, "highlight:"+highlightID)
+	if err != nil {
+		return nil, err
+	}
+
+	return storyMedia(items, "Актуальное")
+}
+
+// reels истории пользователя или актуального по ID ленты
+func (d downloader) reels(ctx context.Context, reelID string) ([]videoData, error) {
+	data, err := d.authorized(ctx, apiURL+"/feed/reels_media/?reel_ids="+netUrl.QueryEscape(reelID))
+	if err != nil {
+		return nil, err
+	}
+
+	var reels apiReels
+	if err := easyjson.Unmarshal(data, &reels); err != nil {
+		return nil, fmt.Errorf("api: %w", err)
+	}
+
+	items := reels.Reels[reelID].Items
+	if len(items) == 0 {
+		return nil, downloaders.ErrNotFound
+	}
+
+	return items, nil
+}
+
+// storyMedia истории одного автора как альбом
+func storyMedia(stories []videoData, title string) (*downloaders.Media, error) {
+	media := &downloaders.Media{
+		Title: title,
+		Author: downloaders.Author{
+			Username:  stories[0].User.Username,
+			Name:      stories[0].User.FullName,
+			AvatarURL: stories[0].User.ProfilePicURL,
+		},
+	}
+
+	for _, story := range stories {
+		if item, ok := extractItem(story.mediaData); ok {
+			media.Items = append(media.Items, item)
+		}
+	}
+
+	if len(media.Items) == 0 {
+		return nil, downloaders.ErrUnsupportedContent
+	}
+
+	if stories[0].TakenAt != 0 {
+		media.UploadedAt = time.Unix(stories[0].TakenAt, 0)
+	}
+
+	return media, nil
+}
+
+// mediaID числовой ID публикации из shortcode.
+// У закрытых публикаций shortcode длиннее, ID кодируют первые 11 символов
+func mediaID(shortcode string) (string, error) {
+	var id uint64
+
+	for _, c := range shortcode[:min(11, len(shortcode))] {
+		i := strings.IndexRune(shortcodeAlphabet, c)
+		if i == -1 {
+			return "", fmt.Errorf("%w: shortcode %s", downloaders.ErrNotMediaURL, shortcode)
+		}
+
+		id = id*64 + uint64(i)
+	}
+
+	return strconv.FormatUint(id, 10), nil
+}
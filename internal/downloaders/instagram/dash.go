@@ -0,0 +1,62 @@
+package instagram
+
+import (
+	"encoding/xml"
+	"regexp"
+	"strconv"
+)
+
+// mpd интересующие нас поля DASH манифеста из video_dash_manifest
+type mpd struct {
+	Duration string `xml:"mediaPresentationDuration,attr"`
+	Periods  []struct {
+		Duration string `xml:"duration,attr"`
+	} `xml:"Period"`
+}
+
+// isoDurationRe длительность ISO 8601 в манифесте: PT1H2M3.5S
+var isoDurationRe = regexp.MustCompile(`^PT(?:([0-9.]+)H)?(?:([0-9.]+)M)?(?:([0-9.]+)S)?$`)
+
+// manifestDuration длительность ролика в секундах из DASH манифеста
+func manifestDuration(manifest string) (float64, error) {
+	var doc mpd
+
+	if err := xml.Unmarshal([]byte(manifest), &doc); err != nil {
+		return 0, err
+	}
+
+	duration := parseISODuration(doc.Duration)
+
+	for _, period := range doc.Periods {
+		if duration == 0 {
+			duration = parseISODuration(period.Duration)
+		}
+	}
+
+	return duration, nil
+}
+
+// parseISODuration секунды из длительности вида PT1M2.5S, 0 если формат не распознан
+func parseISODuration(s string) float64 {
+	m := isoDurationRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+
+	var seconds float64
+
+	for i, unit := range []float64{3600, 60, 1} {
+		if m[i+1] == "" {
+			continue
+		}
+
+		v, err := strconv.ParseFloat(m[i+1], 64)
+		if err != nil {
+			return 0
+		}
+
+		seconds += v * unit
+	}
+
+	return seconds
+}
@@ -1,28 +1,161 @@
 package instagram
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	netUrl "net/url"
 	"strings"
 
 	"github.com/StounhandJ/shorts_forward/internal/downloaders"
 	"github.com/StounhandJ/shorts_forward/internal/utils"
 )
 
+// errLoginWall Instagram требует войти или пройти проверку аккаунта (checkpoint)
+var errLoginWall = fmt.Errorf("%w: стена входа", downloaders.ErrPrivate)
+
 type downloader struct {
 	client *http.Client
+	// authClient без cookie jar, чтобы cookie сессий не смешивались друг с другом и с анонимными запросами
+	authClient *http.Client
+	sessions   *SessionPool
 }
 
-func New(client *http.Client) downloaders.IDownloader {
+// New sessions нужны для историй, актуального и публикаций, которые без входа не открываются
+func New(client *http.Client, sessions *SessionPool) downloaders.IDownloader {
+	authClient := *client
+	authClient.Jar = nil
+
 	return &downloader{
-		client: client,
+		client:     client,
+		authClient: &authClient,
+		sessions:   sessions,
+	}
+}
+
+// strategy способ получить данные публикации по канонической ссылке и shortcode
+type strategy struct {
+	name    string
+	extract func(d downloader, ctx context.Context, url, shortcode string) (*downloaders.Media, error)
+}
+
+// strategies пробуются по порядку: страница публикации, страница встраивания, публичный GraphQL.
+// Instagram часто отдаёт вместо страницы стену входа, а встраивание и GraphQL работают без сессии
+var strategies = []strategy{
+	{name: "html", extract: downloader.fromHTML},
+	{name: "embed", extract: downloader.fromEmbed},
+	{name: "graphql", extract: downloader.fromGraphQL},
+	{name: "api", extract: downloader.fromAPI},
+}
+
+func (d downloader) Download(ctx context.Context, url string) (*downloaders.Media, error) {
+	u, err := netUrl.Parse(url)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := downloaders.PathSegments(u)
+	if len(segments) < 2 {
+		return nil, downloaders.ErrNotMediaURL
+	}
+
+	if segments[0] == "stories" {
+		if segments[1] == "highlights" && len(segments) > 2 {
+			return d.highlight(ctx, segments[2])
+		}
+
+		if len(segments) > 2 {
+			return d.story(ctx, segments[1], segments[2])
+		}
+
+		return nil, downloaders.ErrStoryURL
+	}
+
+	shortcode := segments[1]
+
+	var errs []error
+
+	for _, s := range strategies {
+		media, err := s.extract(d, ctx, url, shortcode)
+		if err == nil {
+			utils.Metrics.Add("instagram_strategy_"+s.name, 1)
+			utils.Log.Debugf("instagram: %s получен через %s", url, s.name)
+
+			return media, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", s.name, err))
+
+		// Публикации нет или платформа ограничила запросы - остальные способы не помогут
+		if errors.Is(err, downloaders.ErrNotFound) || errors.Is(err, downloaders.ErrUnsupportedContent) ||
+			errors.Is(err, downloaders.ErrRateLimited) || ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, significant(errs)
+}
+
+// significant главная из ошибок способов. Ошибка публикации (закрыта, стена входа) возвращается,
+// только если ни один способ не упал из-за сбоя: иначе сбой выдавался бы за закрытую публикацию
+// и предохранитель не срабатывал. Способы без данных на странице и без сессий не в счёт
+func significant(errs []error) error {
+	var content, outage error
+
+	for _, err := range errs {
+		switch {
+		case errors.Is(err, errNoData), errors.Is(err, errNoSessions):
+		case downloaders.IsContentError(err):
+			content = cmp.Or(content, err)
+		default:
+			outage = cmp.Or(outage, err)
+		}
+	}
+
+	if err := cmp.Or(outage, content); err != nil {
+		return err
+	}
+
+	return errs[0]
+}
+
+// fromHTML данные из json, встроенного в страницу публикации
+func (d downloader) fromHTML(ctx context.Context, url, _ string) (*downloaders.Media, error) {
+	req, err := d.pageRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := open(d.client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(resp)
+
+	data, err := scanPage(resp.Body, maxPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseMedia(data)
+}
+
+// get загружает страницу Instagram
+func (d downloader) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := d.pageRequest(ctx, url)
+	if err != nil {
+		return nil, err
 	}
+
+	return do(d.client, req)
 }
 
-func (d downloader) Download(url string) (*downloaders.Video, error) {
-	req, err := http.NewRequestWithContext(context.TODO(), "GET", url, nil)
+func (d downloader) pageRequest(ctx context.Context, url string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -30,29 +163,95 @@ func (d downloader) Download(url string) (*downloaders.Video, error) {
 	req.Header.Add("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 YaBrowser/25.10.0.0 Safari/537.36")
 	req.Header.Add("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8,application/signed-exchange;v=b3;q=0.7")
 
-	resp, err := d.client.Do(req)
+	return req, nil
+}
+
+// authorized GET запрос к api с cookie очередной живой сессии.
+// Сессия, упёршаяся в стену входа, выводится из ротации, и запрос повторяется со следующей
+func (d downloader) authorized(ctx context.Context, url string) ([]byte, error) {
+	for {
+		s, err := d.sessions.take()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", downloaders.ErrPrivate, err)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Add("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 YaBrowser/25.10.0.0 Safari/537.36")
+		req.Header.Add("X-IG-App-ID", appID)
+		req.Header.Add("Cookie", s.cookie)
+
+		if s.csrf != "" {
+			req.Header.Add("X-CSRFToken", s.csrf)
+		}
+
+		data, err := do(d.authClient, req)
+		if errors.Is(err, errLoginWall) {
+			d.sessions.evict(s, err)
+
+			continue
+		}
+
+		return data, err
+	}
+}
+
+// open выполняет запрос к Instagram, тело успешного ответа закрывает вызывающий
+func open(client *http.Client, req *http.Request) (*http.Response, error) {
+	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, downloaders.Temporary(err)
+	}
+
+	// Без входа или с мёртвой сессией Instagram перенаправляет на вход или проверку аккаунта
+	path := resp.Request.URL.Path
+	if strings.HasPrefix(path, "/accounts/login") || strings.HasPrefix(path, "/challenge") {
+		closeBody(resp)
+
+		return nil, errLoginWall
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			utils.Log.Error(err)
+
+	if resp.StatusCode != http.StatusOK {
+		defer closeBody(resp)
+
+		// api отвечает на мёртвую сессию json с login_required или checkpoint_required
+		data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if err == nil && (bytes.Contains(data, []byte("login_required")) || bytes.Contains(data, []byte("checkpoint_required")) ||
+			bytes.Contains(data, []byte("challenge_required"))) {
+			return nil, errLoginWall
 		}
-	}()
 
-	data, err := io.ReadAll(resp.Body)
+		return nil, downloaders.StatusError(resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+// do выполняет запрос к Instagram и читает ответ целиком, но не больше maxPageSize
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := open(client, req)
 	if err != nil {
 		return nil, err
 	}
+	defer closeBody(resp)
 
-	video, ok := extractFirstVideoURL(string(data))
-	if !ok {
-		return nil, errors.New("html не расшифрован")
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize+1))
+	if err != nil {
+		return nil, downloaders.Temporary(err)
 	}
 
-	return video, nil
+	if len(data) > maxPageSize {
+		return nil, errPageTooLarge
+	}
+
+	return data, nil
 }
 
-func (downloader) Valid(url string) bool {
-	return strings.Contains(url, "www.instagram.com/")
+func closeBody(resp *http.Response) {
+	if err := resp.Body.Close(); err != nil {
+		utils.Log.Error(err)
+	}
 }
@@ -0,0 +1,57 @@
+package instagram
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/mailru/easyjson"
+	"github.com/mailru/easyjson/jlexer"
+)
+
+var embedContextKey = []byte(`"contextJSON":`)
+
+// fromEmbed данные со страницы встраивания /p/CODE/embed/captioned/, она доступна без входа
+func (d downloader) fromEmbed(ctx context.Context, _, shortcode string) (*downloaders.Media, error) {
+	data, err := d.get(ctx, "https://www.instagram.com/p/"+shortcode+"/embed/captioned/")
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := parseEmbed(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return media.toMedia()
+}
+
+// parseEmbed достаёт публикацию из строки contextJSON (json, упакованный в строку)
+func parseEmbed(html []byte) (*graphMedia, error) {
+	start := bytes.Index(html, embedContextKey)
+	if start == -1 {
+		return nil, errNoData
+	}
+
+	lexer := jlexer.Lexer{Data: html[start+len(embedContextKey):]}
+
+	contextJSON := lexer.String()
+	if err := lexer.Error(); err != nil {
+		return nil, fmt.Errorf("contextJSON: %w", err)
+	}
+
+	var embed embedContext
+	if err := easyjson.Unmarshal([]byte(contextJSON), &embed); err != nil {
+		return nil, fmt.Errorf("contextJSON: %w", err)
+	}
+
+	switch {
+	case embed.Context.Media != nil:
+		return embed.Context.Media, nil
+	case embed.GqlData.ShortcodeMedia != nil:
+		return embed.GqlData.ShortcodeMedia, nil
+	default:
+		return nil, errNoData
+	}
+}
@@ -2,9 +2,9 @@
 package instagram
 
 import (
-	"regexp"
-	"strconv"
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/mailru/easyjson"
 
@@ -12,25 +12,67 @@ import (
 	"github.com/StounhandJ/shorts_forward/internal/utils"
 )
 
-// videoData описывает интересующие нас поля из JSON
-//
-// easyjson:json
-type videoData struct {
-	Code          string `json:"code"`
+// errNoData на странице нет данных публикации: её удалили, закрыли или показали стену входа
+var errNoData = errors.New("данные публикации не найдены в html")
+
+// mediaData поля отдельного фото или видео, в том числе элемента карусели
+type mediaData struct {
+	MediaType     int  `json:"media_type"`
+	HasAudio      bool `json:"has_audio"`
 	VideoVersions []struct {
-		URL  string `json:"url"`
-		Type int    `json:"type"`
+		URL    string `json:"url"`
+		Type   int    `json:"type"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
 	} `json:"video_versions"`
 	ImageVersions struct {
 		Candidates []struct {
-			URL string `json:"url"`
+			URL    string `json:"url"`
+			Width  int    `json:"width"`
+			Height int    `json:"height"`
 		} `json:"candidates"`
 	} `json:"image_versions2"`
+	VideoDashManifest string `json:"video_dash_manifest"`
+}
+
+// videoData описывает интересующие нас поля из JSON
+//
+// easyjson:json
+type videoData struct {
+	mediaData
+	// ID вида <pk>_<id автора>
+	ID      string `json:"id"`
+	Code    string `json:"code"`
+	TakenAt int64  `json:"taken_at"`
 	Caption struct {
 		Text string `json:"text"`
 	} `json:"caption"`
-	LikeCount         int    `json:"like_count"`
-	VideoDashManifest string `json:"video_dash_manifest"`
+	User struct {
+		Username      string `json:"username"`
+		FullName      string `json:"full_name"`
+		ProfilePicURL string `json:"profile_pic_url"`
+	} `json:"user"`
+	ClipsMetadata struct {
+		MusicInfo struct {
+			MusicAssetInfo struct {
+				Title                  string `json:"title"`
+				DisplayArtist          string `json:"display_artist"`
+				ProgressiveDownloadURL string `json:"progressive_download_url"`
+				DurationInMs           int    `json:"duration_in_ms"`
+			} `json:"music_asset_info"`
+		} `json:"music_info"`
+		OriginalSoundInfo struct {
+			OriginalAudioTitle     string `json:"original_audio_title"`
+			ProgressiveDownloadURL string `json:"progressive_download_url"`
+			DurationInMs           int    `json:"duration_in_ms"`
+			IgArtist               struct {
+				Username string `json:"username"`
+			} `json:"ig_artist"`
+		} `json:"original_sound_info"`
+	} `json:"clips_metadata"`
+	CarouselMedia []mediaData `json:"carousel_media"`
+	LikeCount     int         `json:"like_count"`
+	CommentCount  int         `json:"comment_count"`
 }
 
 // easyjson:json
@@ -38,132 +80,147 @@ type videoObject struct {
 	Items []videoData `json:"items"`
 }
 
-// extractFirstVideoURL ищет "video_versions":[...] в html и возвращает первый url
-func extractFirstVideoURL(html string) (*downloaders.Video, bool) {
-	// Ищем шаблон "key"\s*:\s*{
-	re := regexp.MustCompile(`"` + regexp.QuoteMeta("xdt_api__v1__media__shortcode__web_info") + `"\s*:\s*{`)
-
-	loc := re.FindStringIndex(html)
-	if loc == nil {
-		return nil, false
+// parseMedia данные публикации из JSON объекта, найденного на странице
+func parseMedia(data []byte) (*downloaders.Media, error) {
+	var obj videoObject
+	if err := easyjson.Unmarshal(data, &obj); err != nil {
+		utils.Log.Error("json unmarshal instagram videoObject error:", err)
+		return nil, errNoData
 	}
 
-	// индекс открывающей фигурной скобки:
-	openIdx := strings.Index(html[loc[0]:loc[1]], "{")
-	if openIdx == -1 {
-		return nil, false
+	if len(obj.Items) == 0 {
+		return nil, errNoData
 	}
-	// глобальный индекс открывающей скобки
-	start := loc[0] + openIdx
-
-	// Проходим посимвольно, учитывая строки и экранирование, чтобы найти соответствующую закрывающую }
-	depth := 0
-	inStr := false
-	escaped := false
-
-	var end int
-	for i := start; i < len(html); i++ {
-		c := html[i]
-
-		if escaped {
-			// если предыдущий был '\', пропускаем обработку этого символа
-			escaped = false
-
-			continue
-		}
 
-		if c == '\\' && inStr {
-			escaped = true
-
-			continue
-		}
+	return postMedia(obj.Items[0])
+}
 
-		if c == '"' {
-			inStr = !inStr
+// postMedia публикация в формате api: один элемент или карусель
+func postMedia(post videoData) (*downloaders.Media, error) {
+	// Карусель состоит из нескольких фото и видео, иначе в публикации один элемент
+	parts := post.CarouselMedia
+	if len(parts) == 0 {
+		parts = []mediaData{post.mediaData}
+	}
 
-			continue
+	items := make([]downloaders.Item, 0, len(parts))
+	for _, part := range parts {
+		if item, ok := extractItem(part); ok {
+			items = append(items, item)
 		}
+	}
 
-		if !inStr {
-			if c == '{' {
-				depth++
-			} else if c == '}' {
-				depth--
-				if depth == 0 {
-					end = i
-
-					break
-				}
-			}
-		}
+	if len(items) == 0 {
+		return nil, downloaders.ErrUnsupportedContent
 	}
 
-	if end == 0 {
-		return nil, false
+	title := "Instagram"
+	if post.Caption.Text != "" {
+		title = post.Caption.Text
 	}
 
-	var obj videoObject
-	if err := easyjson.Unmarshal([]byte(html[start:end+1]), &obj); err != nil {
-		utils.Log.Error("json unmarshal instagram videoObject error:", err)
-		return nil, false
+	media := &downloaders.Media{
+		Title:        title,
+		LikeCount:    post.LikeCount,
+		CommentCount: post.CommentCount,
+		ViewCount:    0, // Доступно только через API с авторизацией
+		Author: downloaders.Author{
+			Username:  post.User.Username,
+			Name:      post.User.FullName,
+			AvatarURL: post.User.ProfilePicURL,
+		},
+		Music: extractMusic(post),
+		Items: items,
 	}
 
-	if len(obj.Items) == 0 {
-		return nil, false
+	if post.TakenAt != 0 {
+		media.UploadedAt = time.Unix(post.TakenAt, 0)
 	}
 
-	// Найдём первый непустой url video
-	var videoURL string
-	for _, v := range obj.Items[0].VideoVersions {
-		if strings.TrimSpace(v.URL) != "" {
-			videoURL = v.URL
+	return media, nil
+}
 
-			break
-		}
+// extractItem собирает варианты файла фото или видео
+func extractItem(data mediaData) (downloaders.Item, bool) {
+	item := downloaders.Item{
+		Type: downloaders.MediaTypeVideo,
 	}
 
-	if videoURL == "" {
-		return nil, false
+	if data.VideoDashManifest != "" {
+		duration, err := manifestDuration(data.VideoDashManifest)
+		if err != nil {
+			utils.Log.Debug("instagram dash manifest: ", err)
+		}
+
+		item.Duration = int(duration)
 	}
 
 	// Найдём первый непустой url img
-	var thumbnailURL string
-	for _, v := range obj.Items[0].ImageVersions.Candidates {
+	for _, v := range data.ImageVersions.Candidates {
 		if strings.TrimSpace(v.URL) != "" {
-			thumbnailURL = v.URL
+			item.ThumbnailURL = v.URL
 
 			break
 		}
 	}
 
-	title := "Instagram"
-	if obj.Items[0].Caption.Text != "" {
-		title = obj.Items[0].Caption.Text
+	for _, v := range data.VideoVersions {
+		if strings.TrimSpace(v.URL) == "" {
+			continue
+		}
+
+		item.Renditions = append(item.Renditions, downloaders.Rendition{
+			URL:      v.URL,
+			MimeType: "video/mp4",
+			Width:    v.Width,
+			Height:   v.Height,
+			HasAudio: data.HasAudio,
+		})
 	}
 
-	return &downloaders.Video{
-		Title:        title,
-		VideoURL:     videoURL,
-		MimeType:     "video/mp4",
-		ThumbnailURL: thumbnailURL,
-		Duration:     extractDurationSeconds(obj.Items[0].VideoDashManifest),
-		LikeCount:    obj.Items[0].LikeCount,
-		ViewCount:    0, // Доступно только через API с авторизацией
-	}, true
-}
+	if len(item.Renditions) > 0 {
+		return item, true
+	}
+
+	// Видео нет - значит это фото
+	item.Type = downloaders.MediaTypePhoto
+	item.Duration = 0
 
-func extractDurationSeconds(s string) int {
-	re := regexp.MustCompile(`duration="PT([0-9]+)(?:\.[0-9]+)?S"`)
+	for _, v := range data.ImageVersions.Candidates {
+		if strings.TrimSpace(v.URL) == "" {
+			continue
+		}
 
-	m := re.FindStringSubmatch(s)
-	if len(m) < 2 {
-		return 0
+		item.Renditions = append(item.Renditions, downloaders.Rendition{
+			URL:      v.URL,
+			MimeType: "image/jpeg",
+			Width:    v.Width,
+			Height:   v.Height,
+		})
 	}
 
-	i, err := strconv.Atoi(m[1])
-	if err != nil {
-		return 0
+	return item, len(item.Renditions) > 0
+}
+
+// extractMusic музыка из каталога Instagram либо оригинальный звук автора
+func extractMusic(item videoData) downloaders.Music {
+	asset := item.ClipsMetadata.MusicInfo.MusicAssetInfo
+	if asset.Title != "" {
+		return downloaders.Music{
+			Title:    asset.Title,
+			Author:   asset.DisplayArtist,
+			URL:      asset.ProgressiveDownloadURL,
+			Duration: asset.DurationInMs / 1000,
+		}
 	}
 
-	return i
+	sound := item.ClipsMetadata.OriginalSoundInfo
+
+	return downloaders.Music{
+		Title:    sound.OriginalAudioTitle,
+		Author:   sound.IgArtist.Username,
+		URL:      sound.ProgressiveDownloadURL,
+		Duration: sound.DurationInMs / 1000,
+		Original: sound.OriginalAudioTitle != "",
+	}
 }
@@ -128,12 +128,77 @@ func easyjsonF42599adDecodeGithubComStounhandJShortsForwardInternalDownloadersIn
 		key := in.UnsafeFieldName(false)
 		in.WantColon()
 		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
 		case "code":
 			if in.IsNull() {
 				in.Skip()
 			} else {
 				out.Code = string(in.String())
 			}
+		case "taken_at":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.TakenAt = int64(in.Int64())
+			}
+		case "caption":
+			easyjsonF42599adDecode(in, &out.Caption)
+		case "user":
+			easyjsonF42599adDecode1(in, &out.User)
+		case "clips_metadata":
+			easyjsonF42599adDecode2(in, &out.ClipsMetadata)
+		case "carousel_media":
+			if in.IsNull() {
+				in.Skip()
+				out.CarouselMedia = nil
+			} else {
+				in.Delim('[')
+				if out.CarouselMedia == nil {
+					if !in.IsDelim(']') {
+						out.CarouselMedia = make([]mediaData, 0, 0)
+					} else {
+						out.CarouselMedia = []mediaData{}
+					}
+				} else {
+					out.CarouselMedia = (out.CarouselMedia)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 mediaData
+					easyjsonF42599adDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram2(in, &v4)
+					out.CarouselMedia = append(out.CarouselMedia, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "like_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.LikeCount = int(in.Int())
+			}
+		case "comment_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CommentCount = int(in.Int())
+			}
+		case "media_type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.MediaType = int(in.Int())
+			}
+		case "has_audio":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.HasAudio = bool(in.Bool())
+			}
 		case "video_versions":
 			if in.IsNull() {
 				in.Skip()
@@ -143,39 +208,37 @@ func easyjsonF42599adDecodeGithubComStounhandJShortsForwardInternalDownloadersIn
 				if out.VideoVersions == nil {
 					if !in.IsDelim(']') {
 						out.VideoVersions = make([]struct {
-							URL  string `json:"url"`
-							Type int    `json:"type"`
-						}, 0, 2)
+							URL    string `json:"url"`
+							Type   int    `json:"type"`
+							Width  int    `json:"width"`
+							Height int    `json:"height"`
+						}, 0, 1)
 					} else {
 						out.VideoVersions = []struct {
-							URL  string `json:"url"`
-							Type int    `json:"type"`
+							URL    string `json:"url"`
+							Type   int    `json:"type"`
+							Width  int    `json:"width"`
+							Height int    `json:"height"`
 						}{}
 					}
 				} else {
 					out.VideoVersions = (out.VideoVersions)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v4 struct {
-						URL  string `json:"url"`
-						Type int    `json:"type"`
+					var v5 struct {
+						URL    string `json:"url"`
+						Type   int    `json:"type"`
+						Width  int    `json:"width"`
+						Height int    `json:"height"`
 					}
-					easyjsonF42599adDecode(in, &v4)
-					out.VideoVersions = append(out.VideoVersions, v4)
+					easyjsonF42599adDecode3(in, &v5)
+					out.VideoVersions = append(out.VideoVersions, v5)
 					in.WantComma()
 				}
 				in.Delim(']')
 			}
 		case "image_versions2":
-			easyjsonF42599adDecode1(in, &out.ImageVersions)
-		case "caption":
-			easyjsonF42599adDecode2(in, &out.Caption)
-		case "like_count":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				out.LikeCount = int(in.Int())
-			}
+			easyjsonF42599adDecode4(in, &out.ImageVersions)
 		case "video_dash_manifest":
 			if in.IsNull() {
 				in.Skip()
@@ -197,40 +260,91 @@ func easyjsonF42599adEncodeGithubComStounhandJShortsForwardInternalDownloadersIn
 	first := true
 	_ = first
 	{
-		const prefix string = ",\"code\":"
+		const prefix string = ",\"id\":"
 		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"code\":"
+		out.RawString(prefix)
 		out.String(string(in.Code))
 	}
 	{
-		const prefix string = ",\"video_versions\":"
+		const prefix string = ",\"taken_at\":"
 		out.RawString(prefix)
-		if in.VideoVersions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+		out.Int64(int64(in.TakenAt))
+	}
+	{
+		const prefix string = ",\"caption\":"
+		out.RawString(prefix)
+		easyjsonF42599adEncode(out, in.Caption)
+	}
+	{
+		const prefix string = ",\"user\":"
+		out.RawString(prefix)
+		easyjsonF42599adEncode1(out, in.User)
+	}
+	{
+		const prefix string = ",\"clips_metadata\":"
+		out.RawString(prefix)
+		easyjsonF42599adEncode2(out, in.ClipsMetadata)
+	}
+	{
+		const prefix string = ",\"carousel_media\":"
+		out.RawString(prefix)
+		if in.CarouselMedia == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v5, v6 := range in.VideoVersions {
-				if v5 > 0 {
+			for v6, v7 := range in.CarouselMedia {
+				if v6 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonF42599adEncode(out, v6)
+				easyjsonF42599adEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram2(out, v7)
 			}
 			out.RawByte(']')
 		}
 	}
 	{
-		const prefix string = ",\"image_versions2\":"
+		const prefix string = ",\"like_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.LikeCount))
+	}
+	{
+		const prefix string = ",\"comment_count\":"
 		out.RawString(prefix)
-		easyjsonF42599adEncode1(out, in.ImageVersions)
+		out.Int(int(in.CommentCount))
 	}
 	{
-		const prefix string = ",\"caption\":"
+		const prefix string = ",\"media_type\":"
 		out.RawString(prefix)
-		easyjsonF42599adEncode2(out, in.Caption)
+		out.Int(int(in.MediaType))
 	}
 	{
-		const prefix string = ",\"like_count\":"
+		const prefix string = ",\"has_audio\":"
 		out.RawString(prefix)
-		out.Int(int(in.LikeCount))
+		out.Bool(bool(in.HasAudio))
+	}
+	{
+		const prefix string = ",\"video_versions\":"
+		out.RawString(prefix)
+		if in.VideoVersions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.VideoVersions {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonF42599adEncode3(out, v9)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"image_versions2\":"
+		out.RawString(prefix)
+		easyjsonF42599adEncode4(out, in.ImageVersions)
 	}
 	{
 		const prefix string = ",\"video_dash_manifest\":"
@@ -263,54 +377,11 @@ func (v *videoData) UnmarshalJSON(data []byte) error {
 func (v *videoData) UnmarshalEasyJSON(l *jlexer.Lexer) {
 	easyjsonF42599adDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram1(l, v)
 }
-func easyjsonF42599adDecode2(in *jlexer.Lexer, out *struct {
-	Text string `json:"text"`
-}) {
-	isTopLevel := in.IsStart()
-	if in.IsNull() {
-		if isTopLevel {
-			in.Consumed()
-		}
-		in.Skip()
-		return
-	}
-	in.Delim('{')
-	for !in.IsDelim('}') {
-		key := in.UnsafeFieldName(false)
-		in.WantColon()
-		switch key {
-		case "text":
-			if in.IsNull() {
-				in.Skip()
-			} else {
-				out.Text = string(in.String())
-			}
-		default:
-			in.SkipRecursive()
-		}
-		in.WantComma()
-	}
-	in.Delim('}')
-	if isTopLevel {
-		in.Consumed()
-	}
-}
-func easyjsonF42599adEncode2(out *jwriter.Writer, in struct {
-	Text string `json:"text"`
-}) {
-	out.RawByte('{')
-	first := true
-	_ = first
-	{
-		const prefix string = ",\"text\":"
-		out.RawString(prefix[1:])
-		out.String(string(in.Text))
-	}
-	out.RawByte('}')
-}
-func easyjsonF42599adDecode1(in *jlexer.Lexer, out *struct {
+func easyjsonF42599adDecode4(in *jlexer.Lexer, out *struct {
 	Candidates []struct {
-		URL string `json:"url"`
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
 	} `json:"candidates"`
 }) {
 	isTopLevel := in.IsStart()
@@ -335,22 +406,28 @@ func easyjsonF42599adDecode1(in *jlexer.Lexer, out *struct {
 				if out.Candidates == nil {
 					if !in.IsDelim(']') {
 						out.Candidates = make([]struct {
-							URL string `json:"url"`
-						}, 0, 4)
+							URL    string `json:"url"`
+							Width  int    `json:"width"`
+							Height int    `json:"height"`
+						}, 0, 2)
 					} else {
 						out.Candidates = []struct {
-							URL string `json:"url"`
+							URL    string `json:"url"`
+							Width  int    `json:"width"`
+							Height int    `json:"height"`
 						}{}
 					}
 				} else {
 					out.Candidates = (out.Candidates)[:0]
 				}
 				for !in.IsDelim(']') {
-					var v7 struct {
-						URL string `json:"url"`
+					var v10 struct {
+						URL    string `json:"url"`
+						Width  int    `json:"width"`
+						Height int    `json:"height"`
 					}
-					easyjsonF42599adDecode3(in, &v7)
-					out.Candidates = append(out.Candidates, v7)
+					easyjsonF42599adDecode5(in, &v10)
+					out.Candidates = append(out.Candidates, v10)
 					in.WantComma()
 				}
 				in.Delim(']')
@@ -365,9 +442,11 @@ func easyjsonF42599adDecode1(in *jlexer.Lexer, out *struct {
 		in.Consumed()
 	}
 }
-func easyjsonF42599adEncode1(out *jwriter.Writer, in struct {
+func easyjsonF42599adEncode4(out *jwriter.Writer, in struct {
 	Candidates []struct {
-		URL string `json:"url"`
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
 	} `json:"candidates"`
 }) {
 	out.RawByte('{')
@@ -380,19 +459,21 @@ func easyjsonF42599adEncode1(out *jwriter.Writer, in struct {
 			out.RawString("null")
 		} else {
 			out.RawByte('[')
-			for v8, v9 := range in.Candidates {
-				if v8 > 0 {
+			for v11, v12 := range in.Candidates {
+				if v11 > 0 {
 					out.RawByte(',')
 				}
-				easyjsonF42599adEncode3(out, v9)
+				easyjsonF42599adEncode5(out, v12)
 			}
 			out.RawByte(']')
 		}
 	}
 	out.RawByte('}')
 }
-func easyjsonF42599adDecode3(in *jlexer.Lexer, out *struct {
-	URL string `json:"url"`
+func easyjsonF42599adDecode5(in *jlexer.Lexer, out *struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
@@ -413,6 +494,18 @@ func easyjsonF42599adDecode3(in *jlexer.Lexer, out *struct {
 			} else {
 				out.URL = string(in.String())
 			}
+		case "width":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Width = int(in.Int())
+			}
+		case "height":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Height = int(in.Int())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -423,8 +516,10 @@ func easyjsonF42599adDecode3(in *jlexer.Lexer, out *struct {
 		in.Consumed()
 	}
 }
-func easyjsonF42599adEncode3(out *jwriter.Writer, in struct {
-	URL string `json:"url"`
+func easyjsonF42599adEncode5(out *jwriter.Writer, in struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }) {
 	out.RawByte('{')
 	first := true
@@ -434,11 +529,23 @@ func easyjsonF42599adEncode3(out *jwriter.Writer, in struct {
 		out.RawString(prefix[1:])
 		out.String(string(in.URL))
 	}
+	{
+		const prefix string = ",\"width\":"
+		out.RawString(prefix)
+		out.Int(int(in.Width))
+	}
+	{
+		const prefix string = ",\"height\":"
+		out.RawString(prefix)
+		out.Int(int(in.Height))
+	}
 	out.RawByte('}')
 }
-func easyjsonF42599adDecode(in *jlexer.Lexer, out *struct {
-	URL  string `json:"url"`
-	Type int    `json:"type"`
+func easyjsonF42599adDecode3(in *jlexer.Lexer, out *struct {
+	URL    string `json:"url"`
+	Type   int    `json:"type"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }) {
 	isTopLevel := in.IsStart()
 	if in.IsNull() {
@@ -465,6 +572,18 @@ func easyjsonF42599adDecode(in *jlexer.Lexer, out *struct {
 			} else {
 				out.Type = int(in.Int())
 			}
+		case "width":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Width = int(in.Int())
+			}
+		case "height":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Height = int(in.Int())
+			}
 		default:
 			in.SkipRecursive()
 		}
@@ -475,9 +594,11 @@ func easyjsonF42599adDecode(in *jlexer.Lexer, out *struct {
 		in.Consumed()
 	}
 }
-func easyjsonF42599adEncode(out *jwriter.Writer, in struct {
-	URL  string `json:"url"`
-	Type int    `json:"type"`
+func easyjsonF42599adEncode3(out *jwriter.Writer, in struct {
+	URL    string `json:"url"`
+	Type   int    `json:"type"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
 }) {
 	out.RawByte('{')
 	first := true
@@ -492,5 +613,597 @@ func easyjsonF42599adEncode(out *jwriter.Writer, in struct {
 		out.RawString(prefix)
 		out.Int(int(in.Type))
 	}
+	{
+		const prefix string = ",\"width\":"
+		out.RawString(prefix)
+		out.Int(int(in.Width))
+	}
+	{
+		const prefix string = ",\"height\":"
+		out.RawString(prefix)
+		out.Int(int(in.Height))
+	}
+	out.RawByte('}')
+}
+func easyjsonF42599adDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram2(in *jlexer.Lexer, out *mediaData) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "media_type":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.MediaType = int(in.Int())
+			}
+		case "has_audio":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.HasAudio = bool(in.Bool())
+			}
+		case "video_versions":
+			if in.IsNull() {
+				in.Skip()
+				out.VideoVersions = nil
+			} else {
+				in.Delim('[')
+				if out.VideoVersions == nil {
+					if !in.IsDelim(']') {
+						out.VideoVersions = make([]struct {
+							URL    string `json:"url"`
+							Type   int    `json:"type"`
+							Width  int    `json:"width"`
+							Height int    `json:"height"`
+						}, 0, 1)
+					} else {
+						out.VideoVersions = []struct {
+							URL    string `json:"url"`
+							Type   int    `json:"type"`
+							Width  int    `json:"width"`
+							Height int    `json:"height"`
+						}{}
+					}
+				} else {
+					out.VideoVersions = (out.VideoVersions)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v13 struct {
+						URL    string `json:"url"`
+						Type   int    `json:"type"`
+						Width  int    `json:"width"`
+						Height int    `json:"height"`
+					}
+					easyjsonF42599adDecode3(in, &v13)
+					out.VideoVersions = append(out.VideoVersions, v13)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "image_versions2":
+			easyjsonF42599adDecode4(in, &out.ImageVersions)
+		case "video_dash_manifest":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.VideoDashManifest = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF42599adEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram2(out *jwriter.Writer, in mediaData) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"media_type\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.MediaType))
+	}
+	{
+		const prefix string = ",\"has_audio\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.HasAudio))
+	}
+	{
+		const prefix string = ",\"video_versions\":"
+		out.RawString(prefix)
+		if in.VideoVersions == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.VideoVersions {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				easyjsonF42599adEncode3(out, v15)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"image_versions2\":"
+		out.RawString(prefix)
+		easyjsonF42599adEncode4(out, in.ImageVersions)
+	}
+	{
+		const prefix string = ",\"video_dash_manifest\":"
+		out.RawString(prefix)
+		out.String(string(in.VideoDashManifest))
+	}
+	out.RawByte('}')
+}
+func easyjsonF42599adDecode2(in *jlexer.Lexer, out *struct {
+	MusicInfo struct {
+		MusicAssetInfo struct {
+			Title                  string `json:"title"`
+			DisplayArtist          string `json:"display_artist"`
+			ProgressiveDownloadURL string `json:"progressive_download_url"`
+			DurationInMs           int    `json:"duration_in_ms"`
+		} `json:"music_asset_info"`
+	} `json:"music_info"`
+	OriginalSoundInfo struct {
+		OriginalAudioTitle     string `json:"original_audio_title"`
+		ProgressiveDownloadURL string `json:"progressive_download_url"`
+		DurationInMs           int    `json:"duration_in_ms"`
+		IgArtist               struct {
+			Username string `json:"username"`
+		} `json:"ig_artist"`
+	} `json:"original_sound_info"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "music_info":
+			easyjsonF42599adDecode6(in, &out.MusicInfo)
+		case "original_sound_info":
+			easyjsonF42599adDecode7(in, &out.OriginalSoundInfo)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF42599adEncode2(out *jwriter.Writer, in struct {
+	MusicInfo struct {
+		MusicAssetInfo struct {
+			Title                  string `json:"title"`
+			DisplayArtist          string `json:"display_artist"`
+			ProgressiveDownloadURL string `json:"progressive_download_url"`
+			DurationInMs           int    `json:"duration_in_ms"`
+		} `json:"music_asset_info"`
+	} `json:"music_info"`
+	OriginalSoundInfo struct {
+		OriginalAudioTitle     string `json:"original_audio_title"`
+		ProgressiveDownloadURL string `json:"progressive_download_url"`
+		DurationInMs           int    `json:"duration_in_ms"`
+		IgArtist               struct {
+			Username string `json:"username"`
+		} `json:"ig_artist"`
+	} `json:"original_sound_info"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"music_info\":"
+		out.RawString(prefix[1:])
+		easyjsonF42599adEncode6(out, in.MusicInfo)
+	}
+	{
+		const prefix string = ",\"original_sound_info\":"
+		out.RawString(prefix)
+		easyjsonF42599adEncode7(out, in.OriginalSoundInfo)
+	}
+	out.RawByte('}')
+}
+func easyjsonF42599adDecode7(in *jlexer.Lexer, out *struct {
+	OriginalAudioTitle     string `json:"original_audio_title"`
+	ProgressiveDownloadURL string `json:"progressive_download_url"`
+	DurationInMs           int    `json:"duration_in_ms"`
+	IgArtist               struct {
+		Username string `json:"username"`
+	} `json:"ig_artist"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "original_audio_title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OriginalAudioTitle = string(in.String())
+			}
+		case "progressive_download_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ProgressiveDownloadURL = string(in.String())
+			}
+		case "duration_in_ms":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DurationInMs = int(in.Int())
+			}
+		case "ig_artist":
+			easyjsonF42599adDecode8(in, &out.IgArtist)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF42599adEncode7(out *jwriter.Writer, in struct {
+	OriginalAudioTitle     string `json:"original_audio_title"`
+	ProgressiveDownloadURL string `json:"progressive_download_url"`
+	DurationInMs           int    `json:"duration_in_ms"`
+	IgArtist               struct {
+		Username string `json:"username"`
+	} `json:"ig_artist"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"original_audio_title\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.OriginalAudioTitle))
+	}
+	{
+		const prefix string = ",\"progressive_download_url\":"
+		out.RawString(prefix)
+		out.String(string(in.ProgressiveDownloadURL))
+	}
+	{
+		const prefix string = ",\"duration_in_ms\":"
+		out.RawString(prefix)
+		out.Int(int(in.DurationInMs))
+	}
+	{
+		const prefix string = ",\"ig_artist\":"
+		out.RawString(prefix)
+		easyjsonF42599adEncode8(out, in.IgArtist)
+	}
+	out.RawByte('}')
+}
+func easyjsonF42599adDecode8(in *jlexer.Lexer, out *struct {
+	Username string `json:"username"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "username":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Username = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF42599adEncode8(out *jwriter.Writer, in struct {
+	Username string `json:"username"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"username\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Username))
+	}
+	out.RawByte('}')
+}
+func easyjsonF42599adDecode6(in *jlexer.Lexer, out *struct {
+	MusicAssetInfo struct {
+		Title                  string `json:"title"`
+		DisplayArtist          string `json:"display_artist"`
+		ProgressiveDownloadURL string `json:"progressive_download_url"`
+		DurationInMs           int    `json:"duration_in_ms"`
+	} `json:"music_asset_info"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "music_asset_info":
+			easyjsonF42599adDecode9(in, &out.MusicAssetInfo)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF42599adEncode6(out *jwriter.Writer, in struct {
+	MusicAssetInfo struct {
+		Title                  string `json:"title"`
+		DisplayArtist          string `json:"display_artist"`
+		ProgressiveDownloadURL string `json:"progressive_download_url"`
+		DurationInMs           int    `json:"duration_in_ms"`
+	} `json:"music_asset_info"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"music_asset_info\":"
+		out.RawString(prefix[1:])
+		easyjsonF42599adEncode9(out, in.MusicAssetInfo)
+	}
+	out.RawByte('}')
+}
+func easyjsonF42599adDecode9(in *jlexer.Lexer, out *struct {
+	Title                  string `json:"title"`
+	DisplayArtist          string `json:"display_artist"`
+	ProgressiveDownloadURL string `json:"progressive_download_url"`
+	DurationInMs           int    `json:"duration_in_ms"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "display_artist":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DisplayArtist = string(in.String())
+			}
+		case "progressive_download_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ProgressiveDownloadURL = string(in.String())
+			}
+		case "duration_in_ms":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DurationInMs = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF42599adEncode9(out *jwriter.Writer, in struct {
+	Title                  string `json:"title"`
+	DisplayArtist          string `json:"display_artist"`
+	ProgressiveDownloadURL string `json:"progressive_download_url"`
+	DurationInMs           int    `json:"duration_in_ms"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"display_artist\":"
+		out.RawString(prefix)
+		out.String(string(in.DisplayArtist))
+	}
+	{
+		const prefix string = ",\"progressive_download_url\":"
+		out.RawString(prefix)
+		out.String(string(in.ProgressiveDownloadURL))
+	}
+	{
+		const prefix string = ",\"duration_in_ms\":"
+		out.RawString(prefix)
+		out.Int(int(in.DurationInMs))
+	}
+	out.RawByte('}')
+}
+func easyjsonF42599adDecode1(in *jlexer.Lexer, out *struct {
+	Username      string `json:"username"`
+	FullName      string `json:"full_name"`
+	ProfilePicURL string `json:"profile_pic_url"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "username":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Username = string(in.String())
+			}
+		case "full_name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FullName = string(in.String())
+			}
+		case "profile_pic_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ProfilePicURL = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF42599adEncode1(out *jwriter.Writer, in struct {
+	Username      string `json:"username"`
+	FullName      string `json:"full_name"`
+	ProfilePicURL string `json:"profile_pic_url"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"username\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Username))
+	}
+	{
+		const prefix string = ",\"full_name\":"
+		out.RawString(prefix)
+		out.String(string(in.FullName))
+	}
+	{
+		const prefix string = ",\"profile_pic_url\":"
+		out.RawString(prefix)
+		out.String(string(in.ProfilePicURL))
+	}
+	out.RawByte('}')
+}
+func easyjsonF42599adDecode(in *jlexer.Lexer, out *struct {
+	Text string `json:"text"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "text":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Text = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjsonF42599adEncode(out *jwriter.Writer, in struct {
+	Text string `json:"text"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Text))
+	}
 	out.RawByte('}')
 }
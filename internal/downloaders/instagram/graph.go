@@ -0,0 +1,183 @@
+//go:generate easyjson graph.go
+package instagram
+
+import (
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+)
+
+// graphNode фото или видео в формате shortcode_media (страница встраивания и GraphQL)
+type graphNode struct {
+	IsVideo    bool   `json:"is_video"`
+	VideoURL   string `json:"video_url"`
+	DisplayURL string `json:"display_url"`
+	Dimensions struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	} `json:"dimensions"`
+	DisplayResources []struct {
+		Src          string `json:"src"`
+		ConfigWidth  int    `json:"config_width"`
+		ConfigHeight int    `json:"config_height"`
+	} `json:"display_resources"`
+	VideoDuration float64 `json:"video_duration"`
+	HasAudio      bool    `json:"has_audio"`
+}
+
+// graphMedia публикация в формате shortcode_media
+//
+// easyjson:json
+type graphMedia struct {
+	graphNode
+	Shortcode      string `json:"shortcode"`
+	TakenAt        int64  `json:"taken_at_timestamp"`
+	VideoViewCount int    `json:"video_view_count"`
+	Owner          struct {
+		Username      string `json:"username"`
+		FullName      string `json:"full_name"`
+		ProfilePicURL string `json:"profile_pic_url"`
+	} `json:"owner"`
+	Caption struct {
+		Edges []struct {
+			Node struct {
+				Text string `json:"text"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"edge_media_to_caption"`
+	Likes struct {
+		Count int `json:"count"`
+	} `json:"edge_media_preview_like"`
+	Comments struct {
+		Count int `json:"count"`
+	} `json:"edge_media_to_comment"`
+	Children struct {
+		Edges []struct {
+			Node graphNode `json:"node"`
+		} `json:"edges"`
+	} `json:"edge_sidecar_to_children"`
+	MusicInfo struct {
+		ArtistName        string `json:"artist_name"`
+		SongName          string `json:"song_name"`
+		UsesOriginalAudio bool   `json:"uses_original_audio"`
+	} `json:"clips_music_attribution_info"`
+}
+
+// toMedia собирает публикацию, карусель раскладывается на отдельные фото и видео
+func (m graphMedia) toMedia() (*downloaders.Media, error) {
+	nodes := []graphNode{m.graphNode}
+	if len(m.Children.Edges) > 0 {
+		nodes = nodes[:0]
+		for _, edge := range m.Children.Edges {
+			nodes = append(nodes, edge.Node)
+		}
+	}
+
+	items := make([]downloaders.Item, 0, len(nodes))
+	for _, node := range nodes {
+		if item, ok := node.toItem(); ok {
+			items = append(items, item)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil, downloaders.ErrUnsupportedContent
+	}
+
+	title := "Instagram"
+	if len(m.Caption.Edges) > 0 && m.Caption.Edges[0].Node.Text != "" {
+		title = m.Caption.Edges[0].Node.Text
+	}
+
+	media := &downloaders.Media{
+		Title:        title,
+		ViewCount:    m.VideoViewCount,
+		LikeCount:    m.Likes.Count,
+		CommentCount: m.Comments.Count,
+		Author: downloaders.Author{
+			Username:  m.Owner.Username,
+			Name:      m.Owner.FullName,
+			AvatarURL: m.Owner.ProfilePicURL,
+		},
+		Music: downloaders.Music{
+			Title:    m.MusicInfo.SongName,
+			Author:   m.MusicInfo.ArtistName,
+			Original: m.MusicInfo.UsesOriginalAudio,
+		},
+		Items: items,
+	}
+
+	if m.TakenAt != 0 {
+		media.UploadedAt = time.Unix(m.TakenAt, 0)
+	}
+
+	return media, nil
+}
+
+func (n graphNode) toItem() (downloaders.Item, bool) {
+	if n.IsVideo && n.VideoURL != "" {
+		return downloaders.Item{
+			Type:         downloaders.MediaTypeVideo,
+			ThumbnailURL: n.DisplayURL,
+			Duration:     int(n.VideoDuration),
+			Renditions: []downloaders.Rendition{{
+				URL:      n.VideoURL,
+				MimeType: "video/mp4",
+				Width:    n.Dimensions.Width,
+				Height:   n.Dimensions.Height,
+				HasAudio: n.HasAudio,
+			}},
+		}, true
+	}
+
+	item := downloaders.Item{
+		Type:         downloaders.MediaTypePhoto,
+		ThumbnailURL: n.DisplayURL,
+	}
+
+	for _, r := range n.DisplayResources {
+		if r.Src == "" {
+			continue
+		}
+
+		item.Renditions = append(item.Renditions, downloaders.Rendition{
+			URL:      r.Src,
+			MimeType: "image/jpeg",
+			Width:    r.ConfigWidth,
+			Height:   r.ConfigHeight,
+		})
+	}
+
+	if len(item.Renditions) == 0 && n.DisplayURL != "" {
+		item.Renditions = append(item.Renditions, downloaders.Rendition{
+			URL:      n.DisplayURL,
+			MimeType: "image/jpeg",
+			Width:    n.Dimensions.Width,
+			Height:   n.Dimensions.Height,
+		})
+	}
+
+	return item, len(item.Renditions) > 0
+}
+
+// embedContext json из contextJSON страницы встраивания, публикация лежит в одном из полей
+//
+// easyjson:json
+type embedContext struct {
+	Context struct {
+		Media *graphMedia `json:"media"`
+	} `json:"context"`
+	GqlData struct {
+		ShortcodeMedia *graphMedia `json:"shortcode_media"`
+	} `json:"gql_data"`
+}
+
+// easyjson:json
+type graphQLResponse struct {
+	Data struct {
+		Media *graphMedia `json:"xdt_shortcode_media"`
+	} `json:"data"`
+	Status       string `json:"status"`
+	Message      string `json:"message"`
+	RequireLogin bool   `json:"require_login"`
+}
@@ -0,0 +1,1360 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package instagram
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson2419208eDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram(in *jlexer.Lexer, out *graphQLResponse) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "data":
+			easyjson2419208eDecode(in, &out.Data)
+		case "status":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Status = string(in.String())
+			}
+		case "message":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Message = string(in.String())
+			}
+		case "require_login":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.RequireLogin = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram(out *jwriter.Writer, in graphQLResponse) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"data\":"
+		out.RawString(prefix[1:])
+		easyjson2419208eEncode(out, in.Data)
+	}
+	{
+		const prefix string = ",\"status\":"
+		out.RawString(prefix)
+		out.String(string(in.Status))
+	}
+	{
+		const prefix string = ",\"message\":"
+		out.RawString(prefix)
+		out.String(string(in.Message))
+	}
+	{
+		const prefix string = ",\"require_login\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.RequireLogin))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v graphQLResponse) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson2419208eEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v graphQLResponse) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson2419208eEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *graphQLResponse) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson2419208eDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *graphQLResponse) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson2419208eDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram(l, v)
+}
+func easyjson2419208eDecode(in *jlexer.Lexer, out *struct {
+	Media *graphMedia `json:"xdt_shortcode_media"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "xdt_shortcode_media":
+			if in.IsNull() {
+				in.Skip()
+				out.Media = nil
+			} else {
+				if out.Media == nil {
+					out.Media = new(graphMedia)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*out.Media).UnmarshalEasyJSON(in)
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode(out *jwriter.Writer, in struct {
+	Media *graphMedia `json:"xdt_shortcode_media"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"xdt_shortcode_media\":"
+		out.RawString(prefix[1:])
+		if in.Media == nil {
+			out.RawString("null")
+		} else {
+			(*in.Media).MarshalEasyJSON(out)
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram1(in *jlexer.Lexer, out *graphMedia) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "shortcode":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Shortcode = string(in.String())
+			}
+		case "taken_at_timestamp":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.TakenAt = int64(in.Int64())
+			}
+		case "video_view_count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.VideoViewCount = int(in.Int())
+			}
+		case "owner":
+			easyjson2419208eDecode1(in, &out.Owner)
+		case "edge_media_to_caption":
+			easyjson2419208eDecode2(in, &out.Caption)
+		case "edge_media_preview_like":
+			easyjson2419208eDecode3(in, &out.Likes)
+		case "edge_media_to_comment":
+			easyjson2419208eDecode3(in, &out.Comments)
+		case "edge_sidecar_to_children":
+			easyjson2419208eDecode4(in, &out.Children)
+		case "clips_music_attribution_info":
+			easyjson2419208eDecode5(in, &out.MusicInfo)
+		case "is_video":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IsVideo = bool(in.Bool())
+			}
+		case "video_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.VideoURL = string(in.String())
+			}
+		case "display_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DisplayURL = string(in.String())
+			}
+		case "dimensions":
+			easyjson2419208eDecode6(in, &out.Dimensions)
+		case "display_resources":
+			if in.IsNull() {
+				in.Skip()
+				out.DisplayResources = nil
+			} else {
+				in.Delim('[')
+				if out.DisplayResources == nil {
+					if !in.IsDelim(']') {
+						out.DisplayResources = make([]struct {
+							Src          string `json:"src"`
+							ConfigWidth  int    `json:"config_width"`
+							ConfigHeight int    `json:"config_height"`
+						}, 0, 2)
+					} else {
+						out.DisplayResources = []struct {
+							Src          string `json:"src"`
+							ConfigWidth  int    `json:"config_width"`
+							ConfigHeight int    `json:"config_height"`
+						}{}
+					}
+				} else {
+					out.DisplayResources = (out.DisplayResources)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 struct {
+						Src          string `json:"src"`
+						ConfigWidth  int    `json:"config_width"`
+						ConfigHeight int    `json:"config_height"`
+					}
+					easyjson2419208eDecode7(in, &v1)
+					out.DisplayResources = append(out.DisplayResources, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "video_duration":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.VideoDuration = float64(in.Float64())
+			}
+		case "has_audio":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.HasAudio = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram1(out *jwriter.Writer, in graphMedia) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"shortcode\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Shortcode))
+	}
+	{
+		const prefix string = ",\"taken_at_timestamp\":"
+		out.RawString(prefix)
+		out.Int64(int64(in.TakenAt))
+	}
+	{
+		const prefix string = ",\"video_view_count\":"
+		out.RawString(prefix)
+		out.Int(int(in.VideoViewCount))
+	}
+	{
+		const prefix string = ",\"owner\":"
+		out.RawString(prefix)
+		easyjson2419208eEncode1(out, in.Owner)
+	}
+	{
+		const prefix string = ",\"edge_media_to_caption\":"
+		out.RawString(prefix)
+		easyjson2419208eEncode2(out, in.Caption)
+	}
+	{
+		const prefix string = ",\"edge_media_preview_like\":"
+		out.RawString(prefix)
+		easyjson2419208eEncode3(out, in.Likes)
+	}
+	{
+		const prefix string = ",\"edge_media_to_comment\":"
+		out.RawString(prefix)
+		easyjson2419208eEncode3(out, in.Comments)
+	}
+	{
+		const prefix string = ",\"edge_sidecar_to_children\":"
+		out.RawString(prefix)
+		easyjson2419208eEncode4(out, in.Children)
+	}
+	{
+		const prefix string = ",\"clips_music_attribution_info\":"
+		out.RawString(prefix)
+		easyjson2419208eEncode5(out, in.MusicInfo)
+	}
+	{
+		const prefix string = ",\"is_video\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.IsVideo))
+	}
+	{
+		const prefix string = ",\"video_url\":"
+		out.RawString(prefix)
+		out.String(string(in.VideoURL))
+	}
+	{
+		const prefix string = ",\"display_url\":"
+		out.RawString(prefix)
+		out.String(string(in.DisplayURL))
+	}
+	{
+		const prefix string = ",\"dimensions\":"
+		out.RawString(prefix)
+		easyjson2419208eEncode6(out, in.Dimensions)
+	}
+	{
+		const prefix string = ",\"display_resources\":"
+		out.RawString(prefix)
+		if in.DisplayResources == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.DisplayResources {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				easyjson2419208eEncode7(out, v3)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"video_duration\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.VideoDuration))
+	}
+	{
+		const prefix string = ",\"has_audio\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.HasAudio))
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v graphMedia) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson2419208eEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram1(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v graphMedia) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson2419208eEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram1(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *graphMedia) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson2419208eDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram1(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *graphMedia) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson2419208eDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram1(l, v)
+}
+func easyjson2419208eDecode7(in *jlexer.Lexer, out *struct {
+	Src          string `json:"src"`
+	ConfigWidth  int    `json:"config_width"`
+	ConfigHeight int    `json:"config_height"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "src":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Src = string(in.String())
+			}
+		case "config_width":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ConfigWidth = int(in.Int())
+			}
+		case "config_height":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ConfigHeight = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode7(out *jwriter.Writer, in struct {
+	Src          string `json:"src"`
+	ConfigWidth  int    `json:"config_width"`
+	ConfigHeight int    `json:"config_height"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"src\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Src))
+	}
+	{
+		const prefix string = ",\"config_width\":"
+		out.RawString(prefix)
+		out.Int(int(in.ConfigWidth))
+	}
+	{
+		const prefix string = ",\"config_height\":"
+		out.RawString(prefix)
+		out.Int(int(in.ConfigHeight))
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecode6(in *jlexer.Lexer, out *struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "width":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Width = int(in.Int())
+			}
+		case "height":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Height = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode6(out *jwriter.Writer, in struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"width\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Width))
+	}
+	{
+		const prefix string = ",\"height\":"
+		out.RawString(prefix)
+		out.Int(int(in.Height))
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecode5(in *jlexer.Lexer, out *struct {
+	ArtistName        string `json:"artist_name"`
+	SongName          string `json:"song_name"`
+	UsesOriginalAudio bool   `json:"uses_original_audio"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "artist_name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ArtistName = string(in.String())
+			}
+		case "song_name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.SongName = string(in.String())
+			}
+		case "uses_original_audio":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UsesOriginalAudio = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode5(out *jwriter.Writer, in struct {
+	ArtistName        string `json:"artist_name"`
+	SongName          string `json:"song_name"`
+	UsesOriginalAudio bool   `json:"uses_original_audio"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"artist_name\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ArtistName))
+	}
+	{
+		const prefix string = ",\"song_name\":"
+		out.RawString(prefix)
+		out.String(string(in.SongName))
+	}
+	{
+		const prefix string = ",\"uses_original_audio\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.UsesOriginalAudio))
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecode4(in *jlexer.Lexer, out *struct {
+	Edges []struct {
+		Node graphNode `json:"node"`
+	} `json:"edges"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "edges":
+			if in.IsNull() {
+				in.Skip()
+				out.Edges = nil
+			} else {
+				in.Delim('[')
+				if out.Edges == nil {
+					if !in.IsDelim(']') {
+						out.Edges = make([]struct {
+							Node graphNode `json:"node"`
+						}, 0, 0)
+					} else {
+						out.Edges = []struct {
+							Node graphNode `json:"node"`
+						}{}
+					}
+				} else {
+					out.Edges = (out.Edges)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 struct {
+						Node graphNode `json:"node"`
+					}
+					easyjson2419208eDecode8(in, &v4)
+					out.Edges = append(out.Edges, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode4(out *jwriter.Writer, in struct {
+	Edges []struct {
+		Node graphNode `json:"node"`
+	} `json:"edges"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"edges\":"
+		out.RawString(prefix[1:])
+		if in.Edges == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.Edges {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				easyjson2419208eEncode8(out, v6)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecode8(in *jlexer.Lexer, out *struct {
+	Node graphNode `json:"node"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "node":
+			easyjson2419208eDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram2(in, &out.Node)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode8(out *jwriter.Writer, in struct {
+	Node graphNode `json:"node"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"node\":"
+		out.RawString(prefix[1:])
+		easyjson2419208eEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram2(out, in.Node)
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram2(in *jlexer.Lexer, out *graphNode) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "is_video":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.IsVideo = bool(in.Bool())
+			}
+		case "video_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.VideoURL = string(in.String())
+			}
+		case "display_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DisplayURL = string(in.String())
+			}
+		case "dimensions":
+			easyjson2419208eDecode6(in, &out.Dimensions)
+		case "display_resources":
+			if in.IsNull() {
+				in.Skip()
+				out.DisplayResources = nil
+			} else {
+				in.Delim('[')
+				if out.DisplayResources == nil {
+					if !in.IsDelim(']') {
+						out.DisplayResources = make([]struct {
+							Src          string `json:"src"`
+							ConfigWidth  int    `json:"config_width"`
+							ConfigHeight int    `json:"config_height"`
+						}, 0, 2)
+					} else {
+						out.DisplayResources = []struct {
+							Src          string `json:"src"`
+							ConfigWidth  int    `json:"config_width"`
+							ConfigHeight int    `json:"config_height"`
+						}{}
+					}
+				} else {
+					out.DisplayResources = (out.DisplayResources)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 struct {
+						Src          string `json:"src"`
+						ConfigWidth  int    `json:"config_width"`
+						ConfigHeight int    `json:"config_height"`
+					}
+					easyjson2419208eDecode7(in, &v7)
+					out.DisplayResources = append(out.DisplayResources, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "video_duration":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.VideoDuration = float64(in.Float64())
+			}
+		case "has_audio":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.HasAudio = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram2(out *jwriter.Writer, in graphNode) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"is_video\":"
+		out.RawString(prefix[1:])
+		out.Bool(bool(in.IsVideo))
+	}
+	{
+		const prefix string = ",\"video_url\":"
+		out.RawString(prefix)
+		out.String(string(in.VideoURL))
+	}
+	{
+		const prefix string = ",\"display_url\":"
+		out.RawString(prefix)
+		out.String(string(in.DisplayURL))
+	}
+	{
+		const prefix string = ",\"dimensions\":"
+		out.RawString(prefix)
+		easyjson2419208eEncode6(out, in.Dimensions)
+	}
+	{
+		const prefix string = ",\"display_resources\":"
+		out.RawString(prefix)
+		if in.DisplayResources == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.DisplayResources {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				easyjson2419208eEncode7(out, v9)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"video_duration\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.VideoDuration))
+	}
+	{
+		const prefix string = ",\"has_audio\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.HasAudio))
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecode3(in *jlexer.Lexer, out *struct {
+	Count int `json:"count"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "count":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Count = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode3(out *jwriter.Writer, in struct {
+	Count int `json:"count"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"count\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Count))
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecode2(in *jlexer.Lexer, out *struct {
+	Edges []struct {
+		Node struct {
+			Text string `json:"text"`
+		} `json:"node"`
+	} `json:"edges"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "edges":
+			if in.IsNull() {
+				in.Skip()
+				out.Edges = nil
+			} else {
+				in.Delim('[')
+				if out.Edges == nil {
+					if !in.IsDelim(']') {
+						out.Edges = make([]struct {
+							Node struct {
+								Text string `json:"text"`
+							} `json:"node"`
+						}, 0, 4)
+					} else {
+						out.Edges = []struct {
+							Node struct {
+								Text string `json:"text"`
+							} `json:"node"`
+						}{}
+					}
+				} else {
+					out.Edges = (out.Edges)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 struct {
+						Node struct {
+							Text string `json:"text"`
+						} `json:"node"`
+					}
+					easyjson2419208eDecode9(in, &v10)
+					out.Edges = append(out.Edges, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode2(out *jwriter.Writer, in struct {
+	Edges []struct {
+		Node struct {
+			Text string `json:"text"`
+		} `json:"node"`
+	} `json:"edges"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"edges\":"
+		out.RawString(prefix[1:])
+		if in.Edges == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.Edges {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				easyjson2419208eEncode9(out, v12)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecode9(in *jlexer.Lexer, out *struct {
+	Node struct {
+		Text string `json:"text"`
+	} `json:"node"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "node":
+			easyjson2419208eDecode10(in, &out.Node)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode9(out *jwriter.Writer, in struct {
+	Node struct {
+		Text string `json:"text"`
+	} `json:"node"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"node\":"
+		out.RawString(prefix[1:])
+		easyjson2419208eEncode10(out, in.Node)
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecode10(in *jlexer.Lexer, out *struct {
+	Text string `json:"text"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "text":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Text = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode10(out *jwriter.Writer, in struct {
+	Text string `json:"text"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"text\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Text))
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecode1(in *jlexer.Lexer, out *struct {
+	Username      string `json:"username"`
+	FullName      string `json:"full_name"`
+	ProfilePicURL string `json:"profile_pic_url"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "username":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Username = string(in.String())
+			}
+		case "full_name":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.FullName = string(in.String())
+			}
+		case "profile_pic_url":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ProfilePicURL = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode1(out *jwriter.Writer, in struct {
+	Username      string `json:"username"`
+	FullName      string `json:"full_name"`
+	ProfilePicURL string `json:"profile_pic_url"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"username\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Username))
+	}
+	{
+		const prefix string = ",\"full_name\":"
+		out.RawString(prefix)
+		out.String(string(in.FullName))
+	}
+	{
+		const prefix string = ",\"profile_pic_url\":"
+		out.RawString(prefix)
+		out.String(string(in.ProfilePicURL))
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram3(in *jlexer.Lexer, out *embedContext) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "context":
+			easyjson2419208eDecode11(in, &out.Context)
+		case "gql_data":
+			easyjson2419208eDecode12(in, &out.GqlData)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram3(out *jwriter.Writer, in embedContext) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"context\":"
+		out.RawString(prefix[1:])
+		easyjson2419208eEncode11(out, in.Context)
+	}
+	{
+		const prefix string = ",\"gql_data\":"
+		out.RawString(prefix)
+		easyjson2419208eEncode12(out, in.GqlData)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v embedContext) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson2419208eEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram3(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v embedContext) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson2419208eEncodeGithubComStounhandJShortsForwardInternalDownloadersInstagram3(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *embedContext) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson2419208eDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram3(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *embedContext) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson2419208eDecodeGithubComStounhandJShortsForwardInternalDownloadersInstagram3(l, v)
+}
+func easyjson2419208eDecode12(in *jlexer.Lexer, out *struct {
+	ShortcodeMedia *graphMedia `json:"shortcode_media"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "shortcode_media":
+			if in.IsNull() {
+				in.Skip()
+				out.ShortcodeMedia = nil
+			} else {
+				if out.ShortcodeMedia == nil {
+					out.ShortcodeMedia = new(graphMedia)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*out.ShortcodeMedia).UnmarshalEasyJSON(in)
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode12(out *jwriter.Writer, in struct {
+	ShortcodeMedia *graphMedia `json:"shortcode_media"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"shortcode_media\":"
+		out.RawString(prefix[1:])
+		if in.ShortcodeMedia == nil {
+			out.RawString("null")
+		} else {
+			(*in.ShortcodeMedia).MarshalEasyJSON(out)
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson2419208eDecode11(in *jlexer.Lexer, out *struct {
+	Media *graphMedia `json:"media"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "media":
+			if in.IsNull() {
+				in.Skip()
+				out.Media = nil
+			} else {
+				if out.Media == nil {
+					out.Media = new(graphMedia)
+				}
+				if in.IsNull() {
+					in.Skip()
+				} else {
+					(*out.Media).UnmarshalEasyJSON(in)
+				}
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson2419208eEncode11(out *jwriter.Writer, in struct {
+	Media *graphMedia `json:"media"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"media\":"
+		out.RawString(prefix[1:])
+		if in.Media == nil {
+			out.RawString("null")
+		} else {
+			(*in.Media).MarshalEasyJSON(out)
+		}
+	}
+	out.RawByte('}')
+}
@@ -0,0 +1,58 @@
+package instagram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	netUrl "net/url"
+	"strings"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/mailru/easyjson"
+)
+
+const (
+	graphQLURL = "https://www.instagram.com/graphql/query"
+	// graphQLDocID запрос PolarisPostActionLoadPostQueryQuery веб-версии
+	graphQLDocID = "8845758582119845"
+	// appID идентификатор веб-приложения Instagram
+	appID = "936619743392459"
+)
+
+// fromGraphQL данные из публичного GraphQL запроса публикации по shortcode
+func (d downloader) fromGraphQL(ctx context.Context, _, shortcode string) (*downloaders.Media, error) {
+	form := netUrl.Values{
+		"doc_id":    {graphQLDocID},
+		"variables": {`{"shortcode":"` + shortcode + `"}`},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, graphQLURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 YaBrowser/25.10.0.0 Safari/537.36")
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("X-IG-App-ID", appID)
+
+	data, err := do(d.client, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp graphQLResponse
+	if err := easyjson.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("graphql: %w", err)
+	}
+
+	switch {
+	case resp.RequireLogin:
+		return nil, downloaders.ErrPrivate
+	case resp.Status != "ok":
+		return nil, fmt.Errorf("graphql: %s: %s", resp.Status, resp.Message)
+	case resp.Data.Media == nil:
+		return nil, downloaders.ErrNotFound
+	}
+
+	return resp.Data.Media.toMedia()
+}
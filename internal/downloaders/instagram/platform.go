@@ -0,0 +1,124 @@
+package instagram
+
+import (
+	"encoding/base64"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+)
+
+var Platform = downloaders.Platform{
+	Name:         "instagram",
+	Hosts:        []string{"instagram.com", "www.instagram.com", "m.instagram.com", "instagr.am", "www.instagr.am"},
+	Canonicalize: canonicalize,
+	Short:        isShort,
+}
+
+var (
+	shortcodeRe = regexp.MustCompile(`^[A-Za-z0-9_-]{5,}$`)
+	storyIDRe   = regexp.MustCompile(`^\d{5,25}$`)
+)
+
+// reserved первые части пути, которые не являются именем пользователя
+var reserved = []string{"accounts", "explore", "direct", "about", "developer", "legal", "web", "api", "reels", "reel", "p", "tv", "stories", "s", "share"}
+
+// isShort ссылки «Поделиться» /share/reel/CODE/ и /share/p/CODE/ ведут редиректом на публикацию
+func isShort(u *url.URL) bool {
+	segments := downloaders.PathSegments(u)
+
+	return len(segments) > 0 && segments[0] == "share"
+}
+
+// canonicalize поддерживает /reel/CODE/, /reels/CODE/, /p/CODE/, /tv/CODE/, /user/reel/CODE/,
+// истории /stories/user/ID/ и актуальное /stories/highlights/ID/, /s/BASE64/.
+// Ссылки на профиль и все истории пользователя отличаются ошибками, чтобы объяснить пользователю, что не так
+func canonicalize(u *url.URL) (string, string, error) {
+	segments := downloaders.PathSegments(u)
+	if len(segments) == 0 {
+		return "", "", downloaders.ErrNotMediaURL
+	}
+
+	switch segments[0] {
+	case "stories":
+		return canonicalizeStory(segments)
+	case "s":
+		return canonicalizeHighlight(segments)
+	case "share":
+		return "", "", downloaders.ErrNotMediaURL
+	case "accounts":
+		// Без сессии ссылка «Поделиться» может вести на вход с публикацией в next
+		next, err := url.Parse(u.Query().Get("next"))
+		if err != nil || next.Path == "" || next.IsAbs() {
+			return "", "", downloaders.ErrNotMediaURL
+		}
+
+		return canonicalize(next)
+	}
+
+	// Ссылки с именем автора: /user/reel/CODE/, /user/p/CODE/
+	if !slices.Contains(reserved, segments[0]) {
+		if len(segments) < 3 {
+			return "", "", downloaders.ErrProfileURL
+		}
+
+		segments = segments[1:]
+	}
+
+	if len(segments) < 2 {
+		return "", "", downloaders.ErrNotMediaURL
+	}
+
+	kind, code := segments[0], segments[1]
+
+	switch kind {
+	case "reel", "reels":
+		kind = "reel"
+	case "p", "tv":
+	default:
+		return "", "", downloaders.ErrNotMediaURL
+	}
+
+	// /reels/audio/ID/ страница звука, а не публикация
+	if code == "audio" || !shortcodeRe.MatchString(code) {
+		return "", "", downloaders.ErrNotMediaURL
+	}
+
+	return "https://www.instagram.com/" + kind + "/" + code + "/", code, nil
+}
+
+// canonicalizeStory /stories/user/ID/ и /stories/highlights/ID/, без ID - все истории пользователя
+func canonicalizeStory(segments []string) (string, string, error) {
+	if len(segments) < 3 || !storyIDRe.MatchString(segments[2]) {
+		return "", "", downloaders.ErrStoryURL
+	}
+
+	return "https://www.instagram.com/stories/" + segments[1] + "/" + segments[2] + "/", segments[2], nil
+}
+
+// canonicalizeHighlight короткая ссылка на актуальное /s/BASE64/, где закодировано "highlight:ID"
+func canonicalizeHighlight(segments []string) (string, string, error) {
+	if len(segments) < 2 {
+		return "", "", downloaders.ErrStoryURL
+	}
+
+	code := segments[1]
+
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		decoded, err := encoding.DecodeString(code)
+		if err != nil {
+			continue
+		}
+
+		id, ok := strings.CutPrefix(string(decoded), "highlight:")
+		if !ok {
+			break
+		}
+
+		return canonicalizeStory([]string{"stories", "highlights", id})
+	}
+
+	return "", "", downloaders.ErrStoryURL
+}
@@ -0,0 +1,169 @@
+package instagram
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+)
+
+const (
+	// maxPageSize дальше этого страница не читается, данные публикации обычно в первых сотнях КБ
+	maxPageSize = 8 << 20
+	scanChunk   = 32 << 10
+)
+
+// errPageTooLarge данные публикации не встретились в пределах maxPageSize
+var errPageTooLarge = errors.New("страница instagram больше лимита")
+
+// dataKey ключ JSON объекта с данными публикации
+var dataKey = []byte(`"xdt_api__v1__media__shortcode__web_info"`)
+
+// pageMarkers признаки страницы без данных публикации, по порядку приоритета
+var pageMarkers = []struct {
+	marker []byte
+	err    error
+}{
+	{[]byte(`"pageID":"httpErrorPage"`), downloaders.ErrNotFound},
+	{[]byte("Sorry, this page isn't available"), downloaders.ErrNotFound},
+	{[]byte(`"is_private":true`), downloaders.ErrPrivate},
+}
+
+// scanPage потоково ищет на странице JSON объект с данными публикации и прекращает чтение
+// на его закрывающей скобке. Память ограничена размером объекта и буфером чтения, а не размером страницы
+func scanPage(r io.Reader, limit int) ([]byte, error) {
+	s := pageScanner{
+		r:     r,
+		limit: limit,
+		chunk: make([]byte, scanChunk),
+	}
+
+	// Хвост прошлого чтения сохраняется, чтобы найти ключ и признаки на границе чтений
+	keep := len(dataKey)
+	for _, m := range pageMarkers {
+		keep = max(keep, len(m.marker))
+	}
+
+	found := make([]bool, len(pageMarkers))
+	window := make([]byte, 0, scanChunk+keep)
+
+	for {
+		p, err := s.next()
+		window = append(window, p...)
+
+		if i := bytes.Index(window, dataKey); i != -1 {
+			return s.object(window[i+len(dataKey):])
+		}
+
+		for j, m := range pageMarkers {
+			found[j] = found[j] || bytes.Contains(window, m.marker)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil, markerError(found)
+		} else if err != nil {
+			return nil, err
+		}
+
+		if len(window) > keep {
+			window = append(window[:0], window[len(window)-keep:]...)
+		}
+	}
+}
+
+// markerError причина, по которой на странице нет данных публикации
+func markerError(found []bool) error {
+	for i, ok := range found {
+		if ok {
+			return fmt.Errorf("%w: %w", pageMarkers[i].err, errNoData)
+		}
+	}
+
+	return errNoData
+}
+
+type pageScanner struct {
+	r     io.Reader
+	limit int
+	read  int
+	chunk []byte
+}
+
+// next очередная часть страницы. Вместе с данными может вернуться io.EOF
+func (s *pageScanner) next() ([]byte, error) {
+	n, err := s.r.Read(s.chunk)
+	s.read += n
+
+	if s.read > s.limit {
+		return nil, errPageTooLarge
+	}
+
+	if err != nil && !errors.Is(err, io.EOF) {
+		return s.chunk[:n], downloaders.Temporary(err)
+	}
+
+	return s.chunk[:n], err
+}
+
+// object дочитывает JSON объект, который начинается после ключа: `"key"\s*:\s*{...}`.
+// Проходим посимвольно, учитывая строки и экранирование, чтобы найти соответствующую закрывающую }
+func (s *pageScanner) object(p []byte) ([]byte, error) {
+	var (
+		obj     []byte
+		started bool
+		depth   int
+		inStr   bool
+		escaped bool
+	)
+
+	for {
+		from := 0
+
+		for i, c := range p {
+			if !started {
+				switch c {
+				case ' ', '\t', '\r', '\n', ':':
+					continue
+				case '{':
+					started = true
+					from = i
+				default:
+					return nil, errNoData
+				}
+			}
+
+			switch {
+			case escaped:
+				// если предыдущий был '\', пропускаем обработку этого символа
+				escaped = false
+			case inStr && c == '\\':
+				escaped = true
+			case c == '"':
+				inStr = !inStr
+			case inStr:
+			case c == '{':
+				depth++
+			case c == '}':
+				depth--
+				if depth == 0 {
+					return append(obj, p[from:i+1]...), nil
+				}
+			}
+		}
+
+		if started {
+			obj = append(obj, p[from:]...)
+		}
+
+		var err error
+
+		p, err = s.next()
+		if errors.Is(err, io.EOF) && len(p) == 0 {
+			return nil, errNoData
+		} else if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	}
+}
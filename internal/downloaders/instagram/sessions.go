@@ -0,0 +1,161 @@
+package instagram
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+)
+
+// session cookie авторизованного аккаунта
+type session struct {
+	name   string
+	cookie string
+	csrf   string
+	dead   bool
+}
+
+// SessionPool сессии Instagram, запросы распределяются по живым сессиям по очереди.
+// Сессия, упёршаяся в стену входа или checkpoint, выводится из ротации до перезапуска
+type SessionPool struct {
+	mu       sync.Mutex
+	sessions []*session
+	next     int
+}
+
+// NewSessionPool из строк cookie вида "sessionid=...; ds_user_id=...; csrftoken=..."
+func NewSessionPool(cookies []string) (*SessionPool, error) {
+	pool := &SessionPool{}
+
+	for i, cookie := range cookies {
+		parsed, err := http.ParseCookie(cookie)
+		if err != nil {
+			return nil, fmt.Errorf("сессия %d: %w", i+1, err)
+		}
+
+		s := &session{
+			name:   fmt.Sprintf("#%d", i+1),
+			cookie: cookie,
+		}
+
+		hasSessionID := false
+
+		for _, c := range parsed {
+			switch c.Name {
+			case "sessionid":
+				hasSessionID = true
+			case "ds_user_id":
+				s.name = c.Value
+			case "csrftoken":
+				s.csrf = c.Value
+			}
+		}
+
+		if !hasSessionID {
+			return nil, fmt.Errorf("сессия %d: нет cookie sessionid", i+1)
+		}
+
+		pool.sessions = append(pool.sessions, s)
+	}
+
+	return pool, nil
+}
+
+// LoadSessions читает файл с cookie сессий, по одной на строку, # - комментарий.
+// Пустой путь - пул без сессий
+func LoadSessions(path string) (*SessionPool, error) {
+	if path == "" {
+		return &SessionPool{}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			utils.Log.Error(err)
+		}
+	}()
+
+	var cookies []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		cookies = append(cookies, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return NewSessionPool(cookies)
+}
+
+// Alive количество живых сессий
+func (p *SessionPool) Alive() int {
+	if p == nil {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	alive := 0
+
+	for _, s := range p.sessions {
+		if !s.dead {
+			alive++
+		}
+	}
+
+	return alive
+}
+
+var errNoSessions = errors.New("нет живых сессий Instagram")
+
+// take следующая живая сессия
+func (p *SessionPool) take() (*session, error) {
+	if p == nil {
+		return nil, errNoSessions
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for range p.sessions {
+		s := p.sessions[p.next%len(p.sessions)]
+		p.next++
+
+		if !s.dead {
+			return s, nil
+		}
+	}
+
+	return nil, errNoSessions
+}
+
+// evict выводит сессию из ротации
+func (p *SessionPool) evict(s *session, reason error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if s.dead {
+		return
+	}
+
+	s.dead = true
+
+	utils.Metrics.Add("instagram_session_evicted", 1)
+	utils.Log.Warnf("instagram: сессия %s выведена из ротации: %s", s.name, reason)
+}
@@ -0,0 +1,108 @@
+package downloaders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+)
+
+// Middleware оборачивает загрузчик сквозной логикой: логирование, метрики, кэш, таймауты
+type Middleware func(next IDownloader) IDownloader
+
+// DownloaderFunc позволяет использовать функцию как IDownloader
+type DownloaderFunc func(ctx context.Context, url string) (*Media, error)
+
+func (f DownloaderFunc) Download(ctx context.Context, url string) (*Media, error) {
+	return f(ctx, url)
+}
+
+// Chain оборачивает загрузчик в middlewares, первый из них - внешний
+func Chain(d IDownloader, middlewares ...Middleware) IDownloader {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		d = middlewares[i](d)
+	}
+
+	return d
+}
+
+// MiddlewareOptions настройки middleware платформы
+type MiddlewareOptions struct {
+	Platform     string
+	Timeout      time.Duration
+	CacheTTL     time.Duration
+	CacheSize    int
+	Retries      int
+	RetryBackoff time.Duration
+}
+
+// DefaultMiddlewares порядок по умолчанию: кэш отвечает до метрик, чтобы метрики считали только запросы к платформе,
+// а таймаут внутри повторов ограничивает каждую попытку отдельно
+var DefaultMiddlewares = []string{"logging", "cache", "metrics", "retry", "timeout"}
+
+var middlewares = map[string]func(opts MiddlewareOptions) Middleware{
+	"logging": func(opts MiddlewareOptions) Middleware { return Logging(opts.Platform) },
+	"metrics": func(opts MiddlewareOptions) Middleware { return Metrics(opts.Platform) },
+	"cache":   func(opts MiddlewareOptions) Middleware { return Cache(opts.Platform, opts.CacheSize, opts.CacheTTL) },
+	"retry":   func(opts MiddlewareOptions) Middleware { return Retry(opts.Platform, opts.Retries, opts.RetryBackoff) },
+	"timeout": func(opts MiddlewareOptions) Middleware { return Timeout(opts.Timeout) },
+}
+
+// Pipeline собирает цепочку middleware по именам из конфига, пустой список - DefaultMiddlewares
+func Pipeline(d IDownloader, names []string, opts MiddlewareOptions) (IDownloader, error) {
+	if len(names) == 0 {
+		names = DefaultMiddlewares
+	}
+
+	chain := make([]Middleware, 0, len(names))
+
+	for _, name := range names {
+		factory, ok := middlewares[name]
+		if !ok {
+			return nil, fmt.Errorf("%s: неизвестный middleware %q", opts.Platform, name)
+		}
+
+		chain = append(chain, factory(opts))
+	}
+
+	return Chain(d, chain...), nil
+}
+
+// Logging пишет в debug лог каждый запрос к загрузчику
+func Logging(platform string) Middleware {
+	return func(next IDownloader) IDownloader {
+		return DownloaderFunc(func(ctx context.Context, url string) (*Media, error) {
+			start := time.Now()
+			media, err := next.Download(ctx, url)
+
+			if err != nil {
+				utils.Log.Debugf("%s: %s за %s: %s", platform, url, time.Since(start), err)
+			} else {
+				utils.Log.Debugf("%s: %s за %s", platform, url, time.Since(start))
+			}
+
+			return media, err
+		})
+	}
+}
+
+// Metrics считает успешные и неудачные запросы и суммарное время ответа платформы
+func Metrics(platform string) Middleware {
+	return func(next IDownloader) IDownloader {
+		return DownloaderFunc(func(ctx context.Context, url string) (*Media, error) {
+			start := time.Now()
+			media, err := next.Download(ctx, url)
+
+			utils.Metrics.Add("download_ms_"+platform, time.Since(start).Milliseconds())
+
+			if err != nil {
+				utils.Metrics.Add("download_error_"+platform, 1)
+			} else {
+				utils.Metrics.Add("download_ok_"+platform, 1)
+			}
+
+			return media, err
+		})
+	}
+}
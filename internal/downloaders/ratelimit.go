@@ -0,0 +1,119 @@
+package downloaders
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+)
+
+// RateLimiter token bucket: rate запросов в секунду, до burst запросов подряд.
+// Запросы сверх лимита ждут в очереди не дольше maxWait
+type RateLimiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	maxWait time.Duration
+}
+
+func NewRateLimiter(rate float64, burst int, maxWait time.Duration) *RateLimiter {
+	burst = max(burst, 1)
+
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		tokens:  float64(burst),
+		last:    time.Now(),
+		maxWait: maxWait,
+	}
+}
+
+// Wait ждёт своей очереди. Если ждать дольше maxWait или дедлайн ctx наступит раньше,
+// сразу возвращается ErrRateLimited
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+
+	var wait time.Duration
+	if l.tokens < 1 {
+		wait = time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+	}
+
+	deadline, ok := ctx.Deadline()
+	if wait > l.maxWait || ok && now.Add(wait).After(deadline) {
+		l.mu.Unlock()
+
+		return ErrRateLimited
+	}
+
+	// Токен резервируется сразу, поэтому следующие запросы встают в очередь за этим
+	l.tokens--
+	l.mu.Unlock()
+
+	if wait == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+
+		return ctx.Err()
+	}
+}
+
+// RateLimitedTransport ограничивает частоту запросов к отдельным хостам.
+// Лимиты задаются через Limit до начала работы клиента
+type RateLimitedTransport struct {
+	base     http.RoundTripper
+	limiters map[string]*RateLimiter
+}
+
+func NewRateLimitedTransport(base http.RoundTripper) *RateLimitedTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &RateLimitedTransport{
+		base:     base,
+		limiters: make(map[string]*RateLimiter),
+	}
+}
+
+// Limit ограничивает запросы к host
+func (t *RateLimitedTransport) Limit(host string, limiter *RateLimiter) {
+	t.limiters[host] = limiter
+}
+
+func (t *RateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+
+	if limiter, ok := t.limiters[host]; ok {
+		start := time.Now()
+
+		if err := limiter.Wait(req.Context()); err != nil {
+			utils.Metrics.Add("rate_limited_"+host, 1)
+
+			return nil, fmt.Errorf("%s: %w", host, err)
+		}
+
+		utils.Metrics.Add("rate_wait_ms_"+host, time.Since(start).Milliseconds())
+	}
+
+	return t.base.RoundTrip(req)
+}
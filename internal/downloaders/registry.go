@@ -0,0 +1,222 @@
+package downloaders
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/cache"
+)
+
+const (
+	maxRedirects    = 5
+	expandCacheSize = 10000
+	expandCacheTTL  = 24 * time.Hour
+)
+
+var (
+	ErrUnsupportedURL = errors.New("платформа не поддерживается")
+	ErrNotMediaURL    = errors.New("ссылка не ведёт на ролик")
+	ErrProfileURL     = fmt.Errorf("%w: ссылка на профиль", ErrNotMediaURL)
+	ErrStoryURL       = fmt.Errorf("%w: ссылка на историю", ErrNotMediaURL)
+)
+
+// Canonicalizer приводит ссылку платформы к каноническому виду и достаёт ID ролика
+type Canonicalizer func(u *url.URL) (canonicalURL, mediaID string, err error)
+
+// Platform описание платформы для маршрутизации ссылок
+type Platform struct {
+	Name string
+	// Hosts точные хосты (www.instagram.com) или маски поддоменов (*.youtube.com)
+	Hosts        []string
+	Canonicalize Canonicalizer
+	// Short короткая ссылка (vm.tiktok.com/CODE), которую нужно раскрыть редиректами перед Canonicalize
+	Short func(u *url.URL) bool
+}
+
+// Link разобранная ссылка на ролик
+type Link struct {
+	Platform string
+	URL      string
+	MediaID  string
+}
+
+// ID стабильный идентификатор ролика, подходит для ID inline результата (до 64 байт)
+func (l Link) ID() string {
+	return resultID(l.Platform + "_" + l.MediaID)
+}
+
+// ItemID стабильный идентификатор элемента альбома
+func (l Link) ItemID(i int) string {
+	return resultID(l.Platform + "_" + l.MediaID + "_" + strconv.Itoa(i))
+}
+
+func resultID(id string) string {
+	if len(id) <= 64 {
+		return id
+	}
+
+	sum := sha1.Sum([]byte(id))
+
+	return hex.EncodeToString(sum[:])
+}
+
+type route struct {
+	platform   Platform
+	downloader IDownloader
+}
+
+// Registry таблица маршрутизации ссылок по загрузчикам платформ
+type Registry struct {
+	routes   []route
+	client   *http.Client
+	expanded *cache.LRU[string]
+}
+
+// NewRegistry client нужен для раскрытия коротких ссылок, nil - http.DefaultClient
+func NewRegistry(client *http.Client) *Registry {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	// Редиректы обрабатываются вручную, чтобы остановиться на первой не короткой ссылке
+	noRedirect := *client
+	noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &Registry{
+		client:   &noRedirect,
+		expanded: cache.NewLRU[string](expandCacheSize, expandCacheTTL),
+	}
+}
+
+func (r *Registry) Register(platform Platform, downloader IDownloader) {
+	r.routes = append(r.routes, route{
+		platform:   platform,
+		downloader: downloader,
+	})
+}
+
+// Resolve находит загрузчик для ссылки и приводит её к каноническому виду.
+// Короткие ссылки раскрываются запросом к платформе
+func (r *Registry) Resolve(ctx context.Context, rawURL string) (IDownloader, Link, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return nil, Link{}, ErrUnsupportedURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+
+	for _, rt := range r.routes {
+		if !matchHost(host, rt.platform.Hosts) {
+			continue
+		}
+
+		if rt.platform.Short != nil && rt.platform.Short(u) {
+			u, err = r.expand(ctx, rt.platform, u)
+			if err != nil {
+				return nil, Link{}, err
+			}
+		}
+
+		canonicalURL, mediaID, err := rt.platform.Canonicalize(u)
+		if err != nil {
+			return nil, Link{}, err
+		}
+
+		return rt.downloader, Link{
+			Platform: rt.platform.Name,
+			URL:      canonicalURL,
+			MediaID:  mediaID,
+		}, nil
+	}
+
+	return nil, Link{}, ErrUnsupportedURL
+}
+
+// expand идёт по редиректам короткой ссылки до полной ссылки той же платформы
+func (r *Registry) expand(ctx context.Context, platform Platform, u *url.URL) (*url.URL, error) {
+	short := u.String()
+	if expanded, ok := r.expanded.Get(short); ok {
+		return url.Parse(expanded)
+	}
+
+	for range maxRedirects {
+		next, err := r.redirect(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+
+		if !matchHost(strings.ToLower(next.Hostname()), platform.Hosts) {
+			return nil, fmt.Errorf("%w: короткая ссылка ведёт на %s", ErrNotMediaURL, next.Host)
+		}
+
+		u = next
+		if !platform.Short(u) {
+			r.expanded.Set(short, u.String())
+
+			return u, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: слишком много редиректов", ErrNotMediaURL)
+}
+
+// redirect адрес, на который перенаправляет ссылка
+func (r *Registry) redirect(ctx context.Context, u *url.URL) (*url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 YaBrowser/25.10.0.0 Safari/537.36")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, Temporary(err)
+	}
+
+	closeBody(resp.Body)
+
+	location := resp.Header.Get("Location")
+
+	switch {
+	case resp.StatusCode >= http.StatusMultipleChoices && resp.StatusCode < http.StatusBadRequest && location != "":
+		return u.Parse(location)
+	case resp.StatusCode == http.StatusOK:
+		return nil, fmt.Errorf("%w: короткая ссылка никуда не ведёт", ErrNotMediaURL)
+	default:
+		return nil, StatusError(resp.StatusCode)
+	}
+}
+
+func matchHost(host string, patterns []string) bool {
+	for _, p := range patterns {
+		if suffix, ok := strings.CutPrefix(p, "*."); ok {
+			if host == suffix || strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+
+			continue
+		}
+
+		if host == p {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PathSegments разбивает путь ссылки на непустые части
+func PathSegments(u *url.URL) []string {
+	return strings.FieldsFunc(u.Path, func(r rune) bool { return r == '/' })
+}
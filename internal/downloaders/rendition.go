@@ -0,0 +1,54 @@
+package downloaders
+
+import (
+	"fmt"
+	"strings"
+)
+
+// betterQuality сообщает, что a лучше b
+func betterQuality(a, b Rendition) bool {
+	if a.HasAudio != b.HasAudio {
+		return a.HasAudio
+	}
+
+	if a.Watermark != b.Watermark {
+		return !a.Watermark
+	}
+
+	if pa, pb := a.Width*a.Height, b.Width*b.Height; pa != pb {
+		return pa > pb
+	}
+
+	if a.Bitrate != b.Bitrate {
+		return a.Bitrate > b.Bitrate
+	}
+
+	return a.Size > b.Size
+}
+
+// String краткое описание варианта: 720x1280 1850kbps 4.2MB watermark
+func (r Rendition) String() string {
+	parts := make([]string, 0, 4)
+
+	if r.Width != 0 && r.Height != 0 {
+		parts = append(parts, fmt.Sprintf("%dx%d", r.Width, r.Height))
+	}
+
+	if r.Bitrate != 0 {
+		parts = append(parts, fmt.Sprintf("%dkbps", r.Bitrate/1000))
+	}
+
+	if r.Size != 0 {
+		parts = append(parts, fmt.Sprintf("%.1fMB", float64(r.Size)/(1<<20)))
+	}
+
+	if r.Watermark {
+		parts = append(parts, "watermark")
+	}
+
+	if len(parts) == 0 {
+		return "unknown"
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,63 @@
+package downloaders
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+)
+
+// Retry повторяет запрос при временных ошибках до retries раз. Задержка растёт экспоненциально
+// от backoff со случайным разбросом, чтобы повторы разных запросов не приходили к платформе разом.
+// Повтор не делается, если задержка не успевает до дедлайна ctx
+func Retry(platform string, retries int, backoff time.Duration) Middleware {
+	return func(next IDownloader) IDownloader {
+		if retries <= 0 {
+			return next
+		}
+
+		return DownloaderFunc(func(ctx context.Context, url string) (*Media, error) {
+			media, err := next.Download(ctx, url)
+
+			for attempt := 0; attempt < retries && err != nil && IsTemporary(err); attempt++ {
+				if !sleep(ctx, jitter(backoff<<attempt)) {
+					break
+				}
+
+				utils.Metrics.Add("retry_"+platform, 1)
+				utils.Log.Debugf("%s: повтор %d для %s: %s", platform, attempt+1, url, err)
+
+				media, err = next.Download(ctx, url)
+			}
+
+			return media, err
+		})
+	}
+}
+
+// jitter случайная задержка от d/2 до d
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+
+	return d/2 + rand.N(d/2)
+}
+
+// sleep ждёт d, false если ctx завершится раньше
+func sleep(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+		return false
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
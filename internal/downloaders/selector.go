@@ -0,0 +1,109 @@
+package downloaders
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+)
+
+// Selector выбирает лучший вариант файла, который укладывается в ограничение размера.
+// Неизвестный размер видео уточняется HEAD запросом (или запросом первого байта)
+type Selector struct {
+	client *http.Client
+}
+
+func NewSelector(client *http.Client) *Selector {
+	return &Selector{
+		client: client,
+	}
+}
+
+// Select при limit <= 0 размер не ограничен
+func (s *Selector) Select(ctx context.Context, item Item, limit int64) (Rendition, error) {
+	renditions := slices.Clone(item.Renditions)
+	slices.SortStableFunc(renditions, func(a, b Rendition) int {
+		switch {
+		case betterQuality(a, b):
+			return -1
+		case betterQuality(b, a):
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	found := false
+
+	for _, r := range renditions {
+		if r.URL == "" {
+			continue
+		}
+
+		found = true
+
+		if limit <= 0 {
+			return r, nil
+		}
+
+		// Фото почти никогда не упираются в лимит, а проверять каждое фото альбома долго
+		if r.Size == 0 && item.Type == MediaTypeVideo && s.client != nil {
+			r.Size = s.probeSize(ctx, r.URL)
+		}
+
+		// Размер так и не известен - пусть решает ТГ
+		if r.Size == 0 || r.Size <= limit {
+			return r, nil
+		}
+	}
+
+	if !found {
+		return Rendition{}, errors.New("нет доступных вариантов файла")
+	}
+
+	return Rendition{}, ErrTooLarge
+}
+
+// probeSize возвращает 0, если размер узнать не удалось
+func (s *Selector) probeSize(ctx context.Context, url string) int64 {
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return 0
+		}
+
+		req.Header.Add("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 YaBrowser/25.10.0.0 Safari/537.36")
+
+		if method == http.MethodGet {
+			req.Header.Set("Range", "bytes=0-0")
+		}
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			utils.Log.Debug("probe size: ", err)
+
+			return 0
+		}
+
+		if err := resp.Body.Close(); err != nil {
+			utils.Log.Error(err)
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusPartialContent:
+			// Content-Range: bytes 0-0/12345
+			_, total, ok := strings.Cut(resp.Header.Get("Content-Range"), "/")
+			if size, err := strconv.ParseInt(total, 10, 64); ok && err == nil {
+				return size
+			}
+		case resp.StatusCode == http.StatusOK && resp.ContentLength > 0:
+			return resp.ContentLength
+		}
+	}
+
+	return 0
+}
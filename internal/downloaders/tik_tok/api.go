@@ -11,6 +11,7 @@ import (
 
 	netUrl "net/url"
 
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
 	"github.com/StounhandJ/shorts_forward/internal/utils"
 	easyjson "github.com/mailru/easyjson"
 )
@@ -19,16 +20,18 @@ const (
 	BaseUrl = "https://tikwm.com/api/"
 )
 
+// Ошибки tikwm. "Url parsing is failed" приходит и на удалённые, и на закрытые ролики, и на те,
+// что tikwm просто не смог разобрать, поэтому это ошибка провайдера: ответ даст следующий
 var (
-	ErrRateLimit = errors.New("rate limit exceeded")
-	ErrParse     = errors.New("parse error")
-	ErrUnknown   = errors.New("unknown error")
+	ErrRateLimit = fmt.Errorf("tikwm: %w", downloaders.ErrRateLimited)
+	ErrParse     = errors.New("tikwm: url parsing is failed")
+	ErrUnknown   = errors.New("tikwm: unknown error")
 )
 
-func fetchMetadata(client *http.Client, postUrl string) (ApiResponse, error) {
+func fetchMetadata(ctx context.Context, client *http.Client, postUrl string) (ApiResponse, error) {
 	postUrl = fmt.Sprintf("%s?url=%s", BaseUrl, netUrl.QueryEscape(postUrl))
 
-	req, err := http.NewRequestWithContext(context.TODO(), "GET", postUrl, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", postUrl, nil)
 	if err != nil {
 		return ApiResponse{}, err
 	}
@@ -39,7 +42,7 @@ func fetchMetadata(client *http.Client, postUrl string) (ApiResponse, error) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return ApiResponse{}, err
+		return ApiResponse{}, downloaders.Temporary(err)
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -47,9 +50,13 @@ func fetchMetadata(client *http.Client, postUrl string) (ApiResponse, error) {
 		}
 	}()
 
+	if resp.StatusCode != http.StatusOK {
+		return ApiResponse{}, downloaders.StatusError(resp.StatusCode)
+	}
+
 	b, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return ApiResponse{}, err
+		return ApiResponse{}, downloaders.Temporary(err)
 	}
 
 	var data ApiResponse
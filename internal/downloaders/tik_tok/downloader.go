@@ -1,11 +1,11 @@
 package tiktok
 
 import (
+	"context"
 	"net/http"
-	"strings"
+	"time"
 
 	"github.com/StounhandJ/shorts_forward/internal/downloaders"
-	"github.com/StounhandJ/shorts_forward/internal/utils"
 )
 
 type downloader struct {
@@ -18,23 +18,219 @@ func New(client *http.Client) downloaders.IDownloader {
 	}
 }
 
-func (d downloader) Download(url string) (*downloaders.Video, error) {
-	metadata, err := fetchMetadata(d.client, url)
+func (d downloader) Download(ctx context.Context, url string) (*downloaders.Media, error) {
+	metadata, err := fetchMetadata(ctx, d.client, url)
 	if err != nil {
 		return nil, err
 	}
 
-	return &downloaders.Video{
-		Title:        metadata.Data.Title,
-		VideoURL:     utils.StringNotEmptyCoalesce(metadata.Data.Hdplay, metadata.Data.Play, metadata.Data.Wmplay),
-		ThumbnailURL: metadata.Data.OriginCover,
-		Duration:     metadata.Data.Duration,
-		ViewCount:    metadata.Data.PlayCount,
-		LikeCount:    metadata.Data.DiggCount,
-		MimeType:     "video/mp4",
-	}, nil
+	data := metadata.Data
+
+	media := &downloaders.Media{
+		Title:        data.Title,
+		ViewCount:    data.PlayCount,
+		LikeCount:    data.DiggCount,
+		CommentCount: data.CommentCount,
+		ShareCount:   data.ShareCount,
+		Author: downloaders.Author{
+			ID:        data.Author.ID,
+			Username:  data.Author.UniqueID,
+			Name:      data.Author.Nickname,
+			AvatarURL: data.Author.Avatar,
+		},
+		Music: downloaders.Music{
+			Title:    data.MusicInfo.Title,
+			Author:   data.MusicInfo.Author,
+			URL:      data.MusicInfo.Play,
+			Duration: data.MusicInfo.Duration,
+			Original: data.MusicInfo.Original,
+		},
+	}
+
+	if data.CreateTime != 0 {
+		media.UploadedAt = time.Unix(int64(data.CreateTime), 0)
+	}
+
+	// Фото-слайдшоу: play в этом случае содержит только музыку
+	if len(data.Images) > 0 {
+		for _, image := range data.Images {
+			media.Items = append(media.Items, downloaders.Item{
+				Type:         downloaders.MediaTypePhoto,
+				ThumbnailURL: image,
+				Renditions: []downloaders.Rendition{{
+					URL:      image,
+					MimeType: "image/jpeg",
+				}},
+			})
+		}
+
+		return media, nil
+	}
+
+	item := downloaders.Item{
+		Type:         downloaders.MediaTypeVideo,
+		ThumbnailURL: data.OriginCover,
+		Duration:     data.Duration,
+	}
+
+	// Размеры кадра tikwm не отдаёт, качество вариантов различается по битрейту
+	for _, v := range []struct {
+		url       string
+		size      int
+		watermark bool
+	}{
+		{url: data.Hdplay, size: data.HdSize},
+		{url: data.Play, size: data.Size},
+		{url: data.Wmplay, size: data.WmSize, watermark: true},
+	} {
+		if v.url == "" {
+			continue
+		}
+
+		rendition := downloaders.Rendition{
+			URL:       v.url,
+			MimeType:  "video/mp4",
+			Size:      int64(v.size),
+			HasAudio:  true,
+			Watermark: v.watermark,
+		}
+
+		if data.Duration > 0 {
+			rendition.Bitrate = v.size * 8 / data.Duration
+		}
+
+		item.Renditions = append(item.Renditions, rendition)
+	}
+
+	if len(item.Renditions) == 0 {
+		return nil, downloaders.ErrUnsupportedContent
+	}
+
+	media.Items = append(media.Items, item)
+
+	return media, nil
+}
+
+type webDownloader struct {
+	client *http.Client
+}
+
+// NewWeb загрузчик без сторонних api: данные берутся со страницы ролика.
+// Ссылки CDN привязаны к cookie страницы, поэтому клиенту нужен cookie jar,
+// а ТГ по таким ссылкам обычно скачать не может и файл загружается через бота
+func NewWeb(client *http.Client) downloaders.IDownloader {
+	return &webDownloader{
+		client: client,
+	}
 }
 
-func (downloader) Valid(url string) bool {
-	return strings.Contains(url, "vt.tiktok.com/")
+func (d webDownloader) Download(ctx context.Context, url string) (*downloaders.Media, error) {
+	data, err := fetchPage(ctx, d.client, url)
+	if err != nil {
+		return nil, err
+	}
+
+	media := &downloaders.Media{
+		Title:        data.Desc,
+		ViewCount:    data.Stats.PlayCount,
+		LikeCount:    data.Stats.DiggCount,
+		CommentCount: data.Stats.CommentCount,
+		ShareCount:   data.Stats.ShareCount,
+		Author: downloaders.Author{
+			ID:        data.Author.ID,
+			Username:  data.Author.UniqueID,
+			Name:      data.Author.Nickname,
+			AvatarURL: data.Author.AvatarLarger,
+		},
+		Music: downloaders.Music{
+			Title:    data.Music.Title,
+			Author:   data.Music.AuthorName,
+			URL:      data.Music.PlayURL,
+			Duration: data.Music.Duration,
+			Original: data.Music.Original,
+		},
+	}
+
+	if createdAt := data.createdAt(); createdAt != 0 {
+		media.UploadedAt = time.Unix(createdAt, 0)
+	}
+
+	// Фото-слайдшоу
+	if len(data.ImagePost.Images) > 0 {
+		for _, image := range data.ImagePost.Images {
+			if len(image.ImageURL.URLList) == 0 {
+				continue
+			}
+
+			media.Items = append(media.Items, downloaders.Item{
+				Type:         downloaders.MediaTypePhoto,
+				ThumbnailURL: image.ImageURL.URLList[0],
+				Renditions: []downloaders.Rendition{{
+					URL:      image.ImageURL.URLList[0],
+					MimeType: "image/jpeg",
+					Width:    image.ImageWidth,
+					Height:   image.ImageHeight,
+				}},
+			})
+		}
+
+		if len(media.Items) == 0 {
+			return nil, downloaders.ErrUnsupportedContent
+		}
+
+		return media, nil
+	}
+
+	video := data.Video
+	item := downloaders.Item{
+		Type:         downloaders.MediaTypeVideo,
+		ThumbnailURL: video.OriginCover,
+		Duration:     video.Duration,
+	}
+
+	for _, info := range video.BitrateInfo {
+		if len(info.PlayAddr.URLList) == 0 {
+			continue
+		}
+
+		// Размер без числа уточнит Selector
+		size, err := info.PlayAddr.DataSize.Int64()
+		if err != nil {
+			size = 0
+		}
+
+		item.Renditions = append(item.Renditions, downloaders.Rendition{
+			URL:      info.PlayAddr.URLList[0],
+			MimeType: "video/mp4",
+			Width:    info.PlayAddr.Width,
+			Height:   info.PlayAddr.Height,
+			Bitrate:  info.Bitrate,
+			Size:     size,
+			Codec:    info.CodecType,
+			HasAudio: true,
+			Cookies:  true,
+		})
+	}
+
+	// Старые ролики отдаются без списка вариантов
+	if len(item.Renditions) == 0 && video.PlayAddr != "" {
+		item.Renditions = append(item.Renditions, downloaders.Rendition{
+			URL:      video.PlayAddr,
+			MimeType: "video/mp4",
+			Width:    video.Width,
+			Height:   video.Height,
+			Bitrate:  video.Bitrate,
+			Codec:    video.CodecType,
+			HasAudio: true,
+			Cookies:  true,
+		})
+	}
+
+	if len(item.Renditions) == 0 {
+		return nil, downloaders.ErrUnsupportedContent
+	}
+
+	media.Items = append(media.Items, item)
+
+	return media, nil
 }
@@ -0,0 +1,64 @@
+package tiktok
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+)
+
+var Platform = downloaders.Platform{
+	Name:         "tiktok",
+	Hosts:        []string{"tiktok.com", "www.tiktok.com", "m.tiktok.com", "vm.tiktok.com", "vt.tiktok.com"},
+	Canonicalize: canonicalize,
+	Short:        isShort,
+}
+
+var videoIDRe = regexp.MustCompile(`^\d{8,20}$`)
+
+// isShort короткие ссылки vm.tiktok.com/CODE/, vt.tiktok.com/CODE/ и www.tiktok.com/t/CODE/
+func isShort(u *url.URL) bool {
+	switch strings.ToLower(u.Hostname()) {
+	case "vm.tiktok.com", "vt.tiktok.com":
+		return true
+	default:
+		segments := downloaders.PathSegments(u)
+
+		return len(segments) == 2 && segments[0] == "t"
+	}
+}
+
+// canonicalize поддерживает полные ссылки:
+//
+//	www.tiktok.com/@user/video/ID, www.tiktok.com/@user/photo/ID
+//	m.tiktok.com/v/ID.html, www.tiktok.com/embed/v2/ID
+//
+// Короткие ссылки к этому моменту уже раскрыты реестром
+func canonicalize(u *url.URL) (string, string, error) {
+	segments := downloaders.PathSegments(u)
+	kind, id := "video", ""
+
+	switch {
+	case len(segments) == 3 && strings.HasPrefix(segments[0], "@") && (segments[1] == "video" || segments[1] == "photo"):
+		kind, id = segments[1], segments[2]
+	case len(segments) == 2 && segments[0] == "v":
+		id = strings.TrimSuffix(segments[1], ".html")
+	case len(segments) == 2 && (segments[0] == "video" || segments[0] == "embed"):
+		id = segments[1]
+	case len(segments) == 3 && segments[0] == "embed" && segments[1] == "v2":
+		id = segments[2]
+	}
+
+	if len(segments) == 1 && strings.HasPrefix(segments[0], "@") {
+		return "", "", downloaders.ErrProfileURL
+	}
+
+	if !videoIDRe.MatchString(id) {
+		return "", "", downloaders.ErrNotMediaURL
+	}
+
+	// Автора в ссылке не оставляем: он меняет ник, а ссылка должна зависеть только от ролика.
+	// Без имени автора TikTok сам перенаправит на нужную страницу
+	return "https://www.tiktok.com/@/" + kind + "/" + id, id, nil
+}
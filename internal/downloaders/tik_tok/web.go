@@ -0,0 +1,194 @@
+//go:generate easyjson web.go
+package tiktok
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	easyjson "github.com/mailru/easyjson"
+)
+
+// maxPageSize дальше этого страница не читается, данные ролика в начале страницы
+const maxPageSize = 8 << 20
+
+var rehydrationScript = []byte(`id="__UNIVERSAL_DATA_FOR_REHYDRATION__"`)
+
+// Коды statusCode страницы ролика
+const (
+	webStatusOK           = 0
+	webStatusNotFound     = 10204
+	webStatusPrivate      = 10216
+	webStatusPrivateOwner = 10222
+)
+
+var (
+	errNoRehydration = errors.New("tiktok: на странице нет __UNIVERSAL_DATA_FOR_REHYDRATION__")
+	errPageTooLarge  = errors.New("tiktok: страница больше лимита")
+)
+
+// fetchPage загружает страницу ролика (короткие ссылки раскрываются редиректами клиента)
+// и достаёт из неё данные ролика
+func fetchPage(ctx context.Context, client *http.Client, postUrl string) (webItem, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", postUrl, nil)
+	if err != nil {
+		return webItem{}, err
+	}
+
+	req.Header.Add("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 YaBrowser/25.10.0.0 Safari/537.36")
+	req.Header.Add("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8")
+	req.Header.Add("Accept-Language", "en-US,en;q=0.9")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return webItem{}, downloaders.Temporary(err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			utils.Log.Error(err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return webItem{}, downloaders.StatusError(resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize+1))
+	if err != nil {
+		return webItem{}, downloaders.Temporary(err)
+	}
+
+	if len(b) > maxPageSize {
+		return webItem{}, errPageTooLarge
+	}
+
+	return parsePage(b)
+}
+
+// parsePage разбирает json из <script id="__UNIVERSAL_DATA_FOR_REHYDRATION__">
+func parsePage(html []byte) (webItem, error) {
+	start := bytes.Index(html, rehydrationScript)
+	if start == -1 {
+		return webItem{}, errNoRehydration
+	}
+
+	open := bytes.IndexByte(html[start:], '>')
+	if open == -1 {
+		return webItem{}, errNoRehydration
+	}
+
+	start += open + 1
+
+	end := bytes.Index(html[start:], []byte("</script>"))
+	if end == -1 {
+		return webItem{}, errNoRehydration
+	}
+
+	var data webData
+	if err := easyjson.Unmarshal(html[start:start+end], &data); err != nil {
+		return webItem{}, fmt.Errorf("tiktok: %w", err)
+	}
+
+	detail := data.DefaultScope.VideoDetail
+
+	switch detail.StatusCode {
+	case webStatusOK:
+	case webStatusNotFound:
+		return webItem{}, fmt.Errorf("tiktok: %w: %s", downloaders.ErrNotFound, detail.StatusMsg)
+	case webStatusPrivate, webStatusPrivateOwner:
+		return webItem{}, fmt.Errorf("tiktok: %w: %s", downloaders.ErrPrivate, detail.StatusMsg)
+	default:
+		return webItem{}, fmt.Errorf("tiktok: статус страницы %d: %s", detail.StatusCode, detail.StatusMsg)
+	}
+
+	if detail.ItemInfo.ItemStruct.ID == "" {
+		return webItem{}, errNoRehydration
+	}
+
+	return detail.ItemInfo.ItemStruct, nil
+}
+
+// easyjson:json
+type webData struct {
+	DefaultScope struct {
+		VideoDetail struct {
+			StatusCode int    `json:"statusCode"`
+			StatusMsg  string `json:"statusMsg"`
+			ItemInfo   struct {
+				ItemStruct webItem `json:"itemStruct"`
+			} `json:"itemInfo"`
+		} `json:"webapp.video-detail"`
+	} `json:"__DEFAULT_SCOPE__"`
+}
+
+type webItem struct {
+	ID         string `json:"id"`
+	Desc       string `json:"desc"`
+	CreateTime string `json:"createTime"`
+	Video      struct {
+		Width        int    `json:"width"`
+		Height       int    `json:"height"`
+		Duration     int    `json:"duration"`
+		Cover        string `json:"cover"`
+		OriginCover  string `json:"originCover"`
+		PlayAddr     string `json:"playAddr"`
+		DownloadAddr string `json:"downloadAddr"`
+		Bitrate      int    `json:"bitrate"`
+		CodecType    string `json:"codecType"`
+		BitrateInfo  []struct {
+			Bitrate   int    `json:"Bitrate"`
+			CodecType string `json:"CodecType"`
+			PlayAddr  struct {
+				DataSize json.Number `json:"DataSize"`
+				Width    int         `json:"Width"`
+				Height   int         `json:"Height"`
+				URLList  []string    `json:"UrlList"`
+			} `json:"PlayAddr"`
+		} `json:"bitrateInfo"`
+	} `json:"video"`
+	Author struct {
+		ID           string `json:"id"`
+		UniqueID     string `json:"uniqueId"`
+		Nickname     string `json:"nickname"`
+		AvatarLarger string `json:"avatarLarger"`
+	} `json:"author"`
+	Music struct {
+		Title      string `json:"title"`
+		AuthorName string `json:"authorName"`
+		PlayURL    string `json:"playUrl"`
+		Duration   int    `json:"duration"`
+		Original   bool   `json:"original"`
+	} `json:"music"`
+	Stats struct {
+		DiggCount    int `json:"diggCount"`
+		ShareCount   int `json:"shareCount"`
+		CommentCount int `json:"commentCount"`
+		PlayCount    int `json:"playCount"`
+	} `json:"stats"`
+	ImagePost struct {
+		Images []struct {
+			ImageWidth  int `json:"imageWidth"`
+			ImageHeight int `json:"imageHeight"`
+			ImageURL    struct {
+				URLList []string `json:"urlList"`
+			} `json:"imageURL"`
+		} `json:"images"`
+	} `json:"imagePost"`
+}
+
+// createdAt время публикации, на странице оно строкой
+func (i webItem) createdAt() int64 {
+	createdAt, err := strconv.ParseInt(i.CreateTime, 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return createdAt
+}
@@ -0,0 +1,1287 @@
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package tiktok
+
+import (
+	json "encoding/json"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson8a4a3b28DecodeGithubComStounhandJShortsForwardInternalDownloadersTikTok(in *jlexer.Lexer, out *webData) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "__DEFAULT_SCOPE__":
+			easyjson8a4a3b28Decode(in, &out.DefaultScope)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28EncodeGithubComStounhandJShortsForwardInternalDownloadersTikTok(out *jwriter.Writer, in webData) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"__DEFAULT_SCOPE__\":"
+		out.RawString(prefix[1:])
+		easyjson8a4a3b28Encode(out, in.DefaultScope)
+	}
+	out.RawByte('}')
+}
+
+// MarshalJSON supports json.Marshaler interface
+func (v webData) MarshalJSON() ([]byte, error) {
+	w := jwriter.Writer{}
+	easyjson8a4a3b28EncodeGithubComStounhandJShortsForwardInternalDownloadersTikTok(&w, v)
+	return w.Buffer.BuildBytes(), w.Error
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v webData) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson8a4a3b28EncodeGithubComStounhandJShortsForwardInternalDownloadersTikTok(w, v)
+}
+
+// UnmarshalJSON supports json.Unmarshaler interface
+func (v *webData) UnmarshalJSON(data []byte) error {
+	r := jlexer.Lexer{Data: data}
+	easyjson8a4a3b28DecodeGithubComStounhandJShortsForwardInternalDownloadersTikTok(&r, v)
+	return r.Error()
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *webData) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson8a4a3b28DecodeGithubComStounhandJShortsForwardInternalDownloadersTikTok(l, v)
+}
+func easyjson8a4a3b28Decode(in *jlexer.Lexer, out *struct {
+	VideoDetail struct {
+		StatusCode int    `json:"statusCode"`
+		StatusMsg  string `json:"statusMsg"`
+		ItemInfo   struct {
+			ItemStruct webItem `json:"itemStruct"`
+		} `json:"itemInfo"`
+	} `json:"webapp.video-detail"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "webapp.video-detail":
+			easyjson8a4a3b28Decode1(in, &out.VideoDetail)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode(out *jwriter.Writer, in struct {
+	VideoDetail struct {
+		StatusCode int    `json:"statusCode"`
+		StatusMsg  string `json:"statusMsg"`
+		ItemInfo   struct {
+			ItemStruct webItem `json:"itemStruct"`
+		} `json:"itemInfo"`
+	} `json:"webapp.video-detail"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"webapp.video-detail\":"
+		out.RawString(prefix[1:])
+		easyjson8a4a3b28Encode1(out, in.VideoDetail)
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28Decode1(in *jlexer.Lexer, out *struct {
+	StatusCode int    `json:"statusCode"`
+	StatusMsg  string `json:"statusMsg"`
+	ItemInfo   struct {
+		ItemStruct webItem `json:"itemStruct"`
+	} `json:"itemInfo"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "statusCode":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.StatusCode = int(in.Int())
+			}
+		case "statusMsg":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.StatusMsg = string(in.String())
+			}
+		case "itemInfo":
+			easyjson8a4a3b28Decode2(in, &out.ItemInfo)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode1(out *jwriter.Writer, in struct {
+	StatusCode int    `json:"statusCode"`
+	StatusMsg  string `json:"statusMsg"`
+	ItemInfo   struct {
+		ItemStruct webItem `json:"itemStruct"`
+	} `json:"itemInfo"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"statusCode\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.StatusCode))
+	}
+	{
+		const prefix string = ",\"statusMsg\":"
+		out.RawString(prefix)
+		out.String(string(in.StatusMsg))
+	}
+	{
+		const prefix string = ",\"itemInfo\":"
+		out.RawString(prefix)
+		easyjson8a4a3b28Encode2(out, in.ItemInfo)
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28Decode2(in *jlexer.Lexer, out *struct {
+	ItemStruct webItem `json:"itemStruct"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "itemStruct":
+			easyjson8a4a3b28DecodeGithubComStounhandJShortsForwardInternalDownloadersTikTok1(in, &out.ItemStruct)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode2(out *jwriter.Writer, in struct {
+	ItemStruct webItem `json:"itemStruct"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"itemStruct\":"
+		out.RawString(prefix[1:])
+		easyjson8a4a3b28EncodeGithubComStounhandJShortsForwardInternalDownloadersTikTok1(out, in.ItemStruct)
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28DecodeGithubComStounhandJShortsForwardInternalDownloadersTikTok1(in *jlexer.Lexer, out *webItem) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "desc":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Desc = string(in.String())
+			}
+		case "createTime":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CreateTime = string(in.String())
+			}
+		case "video":
+			easyjson8a4a3b28Decode3(in, &out.Video)
+		case "author":
+			easyjson8a4a3b28Decode4(in, &out.Author)
+		case "music":
+			easyjson8a4a3b28Decode5(in, &out.Music)
+		case "stats":
+			easyjson8a4a3b28Decode6(in, &out.Stats)
+		case "imagePost":
+			easyjson8a4a3b28Decode7(in, &out.ImagePost)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28EncodeGithubComStounhandJShortsForwardInternalDownloadersTikTok1(out *jwriter.Writer, in webItem) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"desc\":"
+		out.RawString(prefix)
+		out.String(string(in.Desc))
+	}
+	{
+		const prefix string = ",\"createTime\":"
+		out.RawString(prefix)
+		out.String(string(in.CreateTime))
+	}
+	{
+		const prefix string = ",\"video\":"
+		out.RawString(prefix)
+		easyjson8a4a3b28Encode3(out, in.Video)
+	}
+	{
+		const prefix string = ",\"author\":"
+		out.RawString(prefix)
+		easyjson8a4a3b28Encode4(out, in.Author)
+	}
+	{
+		const prefix string = ",\"music\":"
+		out.RawString(prefix)
+		easyjson8a4a3b28Encode5(out, in.Music)
+	}
+	{
+		const prefix string = ",\"stats\":"
+		out.RawString(prefix)
+		easyjson8a4a3b28Encode6(out, in.Stats)
+	}
+	{
+		const prefix string = ",\"imagePost\":"
+		out.RawString(prefix)
+		easyjson8a4a3b28Encode7(out, in.ImagePost)
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28Decode7(in *jlexer.Lexer, out *struct {
+	Images []struct {
+		ImageWidth  int `json:"imageWidth"`
+		ImageHeight int `json:"imageHeight"`
+		ImageURL    struct {
+			URLList []string `json:"urlList"`
+		} `json:"imageURL"`
+	} `json:"images"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "images":
+			if in.IsNull() {
+				in.Skip()
+				out.Images = nil
+			} else {
+				in.Delim('[')
+				if out.Images == nil {
+					if !in.IsDelim(']') {
+						out.Images = make([]struct {
+							ImageWidth  int `json:"imageWidth"`
+							ImageHeight int `json:"imageHeight"`
+							ImageURL    struct {
+								URLList []string `json:"urlList"`
+							} `json:"imageURL"`
+						}, 0, 1)
+					} else {
+						out.Images = []struct {
+							ImageWidth  int `json:"imageWidth"`
+							ImageHeight int `json:"imageHeight"`
+							ImageURL    struct {
+								URLList []string `json:"urlList"`
+							} `json:"imageURL"`
+						}{}
+					}
+				} else {
+					out.Images = (out.Images)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 struct {
+						ImageWidth  int `json:"imageWidth"`
+						ImageHeight int `json:"imageHeight"`
+						ImageURL    struct {
+							URLList []string `json:"urlList"`
+						} `json:"imageURL"`
+					}
+					easyjson8a4a3b28Decode8(in, &v1)
+					out.Images = append(out.Images, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode7(out *jwriter.Writer, in struct {
+	Images []struct {
+		ImageWidth  int `json:"imageWidth"`
+		ImageHeight int `json:"imageHeight"`
+		ImageURL    struct {
+			URLList []string `json:"urlList"`
+		} `json:"imageURL"`
+	} `json:"images"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"images\":"
+		out.RawString(prefix[1:])
+		if in.Images == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v2, v3 := range in.Images {
+				if v2 > 0 {
+					out.RawByte(',')
+				}
+				easyjson8a4a3b28Encode8(out, v3)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28Decode8(in *jlexer.Lexer, out *struct {
+	ImageWidth  int `json:"imageWidth"`
+	ImageHeight int `json:"imageHeight"`
+	ImageURL    struct {
+		URLList []string `json:"urlList"`
+	} `json:"imageURL"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "imageWidth":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ImageWidth = int(in.Int())
+			}
+		case "imageHeight":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ImageHeight = int(in.Int())
+			}
+		case "imageURL":
+			easyjson8a4a3b28Decode9(in, &out.ImageURL)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode8(out *jwriter.Writer, in struct {
+	ImageWidth  int `json:"imageWidth"`
+	ImageHeight int `json:"imageHeight"`
+	ImageURL    struct {
+		URLList []string `json:"urlList"`
+	} `json:"imageURL"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"imageWidth\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.ImageWidth))
+	}
+	{
+		const prefix string = ",\"imageHeight\":"
+		out.RawString(prefix)
+		out.Int(int(in.ImageHeight))
+	}
+	{
+		const prefix string = ",\"imageURL\":"
+		out.RawString(prefix)
+		easyjson8a4a3b28Encode9(out, in.ImageURL)
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28Decode9(in *jlexer.Lexer, out *struct {
+	URLList []string `json:"urlList"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "urlList":
+			if in.IsNull() {
+				in.Skip()
+				out.URLList = nil
+			} else {
+				in.Delim('[')
+				if out.URLList == nil {
+					if !in.IsDelim(']') {
+						out.URLList = make([]string, 0, 4)
+					} else {
+						out.URLList = []string{}
+					}
+				} else {
+					out.URLList = (out.URLList)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v4 = string(in.String())
+					}
+					out.URLList = append(out.URLList, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode9(out *jwriter.Writer, in struct {
+	URLList []string `json:"urlList"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"urlList\":"
+		out.RawString(prefix[1:])
+		if in.URLList == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.URLList {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28Decode6(in *jlexer.Lexer, out *struct {
+	DiggCount    int `json:"diggCount"`
+	ShareCount   int `json:"shareCount"`
+	CommentCount int `json:"commentCount"`
+	PlayCount    int `json:"playCount"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "diggCount":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DiggCount = int(in.Int())
+			}
+		case "shareCount":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ShareCount = int(in.Int())
+			}
+		case "commentCount":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CommentCount = int(in.Int())
+			}
+		case "playCount":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.PlayCount = int(in.Int())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode6(out *jwriter.Writer, in struct {
+	DiggCount    int `json:"diggCount"`
+	ShareCount   int `json:"shareCount"`
+	CommentCount int `json:"commentCount"`
+	PlayCount    int `json:"playCount"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"diggCount\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.DiggCount))
+	}
+	{
+		const prefix string = ",\"shareCount\":"
+		out.RawString(prefix)
+		out.Int(int(in.ShareCount))
+	}
+	{
+		const prefix string = ",\"commentCount\":"
+		out.RawString(prefix)
+		out.Int(int(in.CommentCount))
+	}
+	{
+		const prefix string = ",\"playCount\":"
+		out.RawString(prefix)
+		out.Int(int(in.PlayCount))
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28Decode5(in *jlexer.Lexer, out *struct {
+	Title      string `json:"title"`
+	AuthorName string `json:"authorName"`
+	PlayURL    string `json:"playUrl"`
+	Duration   int    `json:"duration"`
+	Original   bool   `json:"original"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "title":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Title = string(in.String())
+			}
+		case "authorName":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AuthorName = string(in.String())
+			}
+		case "playUrl":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.PlayURL = string(in.String())
+			}
+		case "duration":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Duration = int(in.Int())
+			}
+		case "original":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Original = bool(in.Bool())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode5(out *jwriter.Writer, in struct {
+	Title      string `json:"title"`
+	AuthorName string `json:"authorName"`
+	PlayURL    string `json:"playUrl"`
+	Duration   int    `json:"duration"`
+	Original   bool   `json:"original"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"title\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Title))
+	}
+	{
+		const prefix string = ",\"authorName\":"
+		out.RawString(prefix)
+		out.String(string(in.AuthorName))
+	}
+	{
+		const prefix string = ",\"playUrl\":"
+		out.RawString(prefix)
+		out.String(string(in.PlayURL))
+	}
+	{
+		const prefix string = ",\"duration\":"
+		out.RawString(prefix)
+		out.Int(int(in.Duration))
+	}
+	{
+		const prefix string = ",\"original\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Original))
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28Decode4(in *jlexer.Lexer, out *struct {
+	ID           string `json:"id"`
+	UniqueID     string `json:"uniqueId"`
+	Nickname     string `json:"nickname"`
+	AvatarLarger string `json:"avatarLarger"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "id":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.ID = string(in.String())
+			}
+		case "uniqueId":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.UniqueID = string(in.String())
+			}
+		case "nickname":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Nickname = string(in.String())
+			}
+		case "avatarLarger":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.AvatarLarger = string(in.String())
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode4(out *jwriter.Writer, in struct {
+	ID           string `json:"id"`
+	UniqueID     string `json:"uniqueId"`
+	Nickname     string `json:"nickname"`
+	AvatarLarger string `json:"avatarLarger"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"uniqueId\":"
+		out.RawString(prefix)
+		out.String(string(in.UniqueID))
+	}
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix)
+		out.String(string(in.Nickname))
+	}
+	{
+		const prefix string = ",\"avatarLarger\":"
+		out.RawString(prefix)
+		out.String(string(in.AvatarLarger))
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28Decode3(in *jlexer.Lexer, out *struct {
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Duration     int    `json:"duration"`
+	Cover        string `json:"cover"`
+	OriginCover  string `json:"originCover"`
+	PlayAddr     string `json:"playAddr"`
+	DownloadAddr string `json:"downloadAddr"`
+	Bitrate      int    `json:"bitrate"`
+	CodecType    string `json:"codecType"`
+	BitrateInfo  []struct {
+		Bitrate   int    `json:"Bitrate"`
+		CodecType string `json:"CodecType"`
+		PlayAddr  struct {
+			DataSize json.Number `json:"DataSize"`
+			Width    int         `json:"Width"`
+			Height   int         `json:"Height"`
+			URLList  []string    `json:"UrlList"`
+		} `json:"PlayAddr"`
+	} `json:"bitrateInfo"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "width":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Width = int(in.Int())
+			}
+		case "height":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Height = int(in.Int())
+			}
+		case "duration":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Duration = int(in.Int())
+			}
+		case "cover":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Cover = string(in.String())
+			}
+		case "originCover":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.OriginCover = string(in.String())
+			}
+		case "playAddr":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.PlayAddr = string(in.String())
+			}
+		case "downloadAddr":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DownloadAddr = string(in.String())
+			}
+		case "bitrate":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Bitrate = int(in.Int())
+			}
+		case "codecType":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CodecType = string(in.String())
+			}
+		case "bitrateInfo":
+			if in.IsNull() {
+				in.Skip()
+				out.BitrateInfo = nil
+			} else {
+				in.Delim('[')
+				if out.BitrateInfo == nil {
+					if !in.IsDelim(']') {
+						out.BitrateInfo = make([]struct {
+							Bitrate   int    `json:"Bitrate"`
+							CodecType string `json:"CodecType"`
+							PlayAddr  struct {
+								DataSize json.Number `json:"DataSize"`
+								Width    int         `json:"Width"`
+								Height   int         `json:"Height"`
+								URLList  []string    `json:"UrlList"`
+							} `json:"PlayAddr"`
+						}, 0, 0)
+					} else {
+						out.BitrateInfo = []struct {
+							Bitrate   int    `json:"Bitrate"`
+							CodecType string `json:"CodecType"`
+							PlayAddr  struct {
+								DataSize json.Number `json:"DataSize"`
+								Width    int         `json:"Width"`
+								Height   int         `json:"Height"`
+								URLList  []string    `json:"UrlList"`
+							} `json:"PlayAddr"`
+						}{}
+					}
+				} else {
+					out.BitrateInfo = (out.BitrateInfo)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v7 struct {
+						Bitrate   int    `json:"Bitrate"`
+						CodecType string `json:"CodecType"`
+						PlayAddr  struct {
+							DataSize json.Number `json:"DataSize"`
+							Width    int         `json:"Width"`
+							Height   int         `json:"Height"`
+							URLList  []string    `json:"UrlList"`
+						} `json:"PlayAddr"`
+					}
+					easyjson8a4a3b28Decode10(in, &v7)
+					out.BitrateInfo = append(out.BitrateInfo, v7)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode3(out *jwriter.Writer, in struct {
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	Duration     int    `json:"duration"`
+	Cover        string `json:"cover"`
+	OriginCover  string `json:"originCover"`
+	PlayAddr     string `json:"playAddr"`
+	DownloadAddr string `json:"downloadAddr"`
+	Bitrate      int    `json:"bitrate"`
+	CodecType    string `json:"codecType"`
+	BitrateInfo  []struct {
+		Bitrate   int    `json:"Bitrate"`
+		CodecType string `json:"CodecType"`
+		PlayAddr  struct {
+			DataSize json.Number `json:"DataSize"`
+			Width    int         `json:"Width"`
+			Height   int         `json:"Height"`
+			URLList  []string    `json:"UrlList"`
+		} `json:"PlayAddr"`
+	} `json:"bitrateInfo"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"width\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Width))
+	}
+	{
+		const prefix string = ",\"height\":"
+		out.RawString(prefix)
+		out.Int(int(in.Height))
+	}
+	{
+		const prefix string = ",\"duration\":"
+		out.RawString(prefix)
+		out.Int(int(in.Duration))
+	}
+	{
+		const prefix string = ",\"cover\":"
+		out.RawString(prefix)
+		out.String(string(in.Cover))
+	}
+	{
+		const prefix string = ",\"originCover\":"
+		out.RawString(prefix)
+		out.String(string(in.OriginCover))
+	}
+	{
+		const prefix string = ",\"playAddr\":"
+		out.RawString(prefix)
+		out.String(string(in.PlayAddr))
+	}
+	{
+		const prefix string = ",\"downloadAddr\":"
+		out.RawString(prefix)
+		out.String(string(in.DownloadAddr))
+	}
+	{
+		const prefix string = ",\"bitrate\":"
+		out.RawString(prefix)
+		out.Int(int(in.Bitrate))
+	}
+	{
+		const prefix string = ",\"codecType\":"
+		out.RawString(prefix)
+		out.String(string(in.CodecType))
+	}
+	{
+		const prefix string = ",\"bitrateInfo\":"
+		out.RawString(prefix)
+		if in.BitrateInfo == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v8, v9 := range in.BitrateInfo {
+				if v8 > 0 {
+					out.RawByte(',')
+				}
+				easyjson8a4a3b28Encode10(out, v9)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28Decode10(in *jlexer.Lexer, out *struct {
+	Bitrate   int    `json:"Bitrate"`
+	CodecType string `json:"CodecType"`
+	PlayAddr  struct {
+		DataSize json.Number `json:"DataSize"`
+		Width    int         `json:"Width"`
+		Height   int         `json:"Height"`
+		URLList  []string    `json:"UrlList"`
+	} `json:"PlayAddr"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "Bitrate":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Bitrate = int(in.Int())
+			}
+		case "CodecType":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.CodecType = string(in.String())
+			}
+		case "PlayAddr":
+			easyjson8a4a3b28Decode11(in, &out.PlayAddr)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode10(out *jwriter.Writer, in struct {
+	Bitrate   int    `json:"Bitrate"`
+	CodecType string `json:"CodecType"`
+	PlayAddr  struct {
+		DataSize json.Number `json:"DataSize"`
+		Width    int         `json:"Width"`
+		Height   int         `json:"Height"`
+		URLList  []string    `json:"UrlList"`
+	} `json:"PlayAddr"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"Bitrate\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.Bitrate))
+	}
+	{
+		const prefix string = ",\"CodecType\":"
+		out.RawString(prefix)
+		out.String(string(in.CodecType))
+	}
+	{
+		const prefix string = ",\"PlayAddr\":"
+		out.RawString(prefix)
+		easyjson8a4a3b28Encode11(out, in.PlayAddr)
+	}
+	out.RawByte('}')
+}
+func easyjson8a4a3b28Decode11(in *jlexer.Lexer, out *struct {
+	DataSize json.Number `json:"DataSize"`
+	Width    int         `json:"Width"`
+	Height   int         `json:"Height"`
+	URLList  []string    `json:"UrlList"`
+}) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		switch key {
+		case "DataSize":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.DataSize = in.JsonNumber()
+			}
+		case "Width":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Width = int(in.Int())
+			}
+		case "Height":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				out.Height = int(in.Int())
+			}
+		case "UrlList":
+			if in.IsNull() {
+				in.Skip()
+				out.URLList = nil
+			} else {
+				in.Delim('[')
+				if out.URLList == nil {
+					if !in.IsDelim(']') {
+						out.URLList = make([]string, 0, 4)
+					} else {
+						out.URLList = []string{}
+					}
+				} else {
+					out.URLList = (out.URLList)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v10 string
+					if in.IsNull() {
+						in.Skip()
+					} else {
+						v10 = string(in.String())
+					}
+					out.URLList = append(out.URLList, v10)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson8a4a3b28Encode11(out *jwriter.Writer, in struct {
+	DataSize json.Number `json:"DataSize"`
+	Width    int         `json:"Width"`
+	Height   int         `json:"Height"`
+	URLList  []string    `json:"UrlList"`
+}) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"DataSize\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.DataSize))
+	}
+	{
+		const prefix string = ",\"Width\":"
+		out.RawString(prefix)
+		out.Int(int(in.Width))
+	}
+	{
+		const prefix string = ",\"Height\":"
+		out.RawString(prefix)
+		out.Int(int(in.Height))
+	}
+	{
+		const prefix string = ",\"UrlList\":"
+		out.RawString(prefix)
+		if in.URLList == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v11, v12 := range in.URLList {
+				if v11 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v12))
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
@@ -0,0 +1,34 @@
+package downloaders
+
+import (
+	"context"
+	"time"
+)
+
+// timeoutDownloader ограничивает время получения данных о ролике для платформы
+type timeoutDownloader struct {
+	IDownloader
+	timeout time.Duration
+}
+
+// Timeout не даёт медленной платформе держать обработчик дольше timeout.
+// При timeout <= 0 загрузчик не оборачивается
+func Timeout(timeout time.Duration) Middleware {
+	return func(next IDownloader) IDownloader {
+		if timeout <= 0 {
+			return next
+		}
+
+		return timeoutDownloader{
+			IDownloader: next,
+			timeout:     timeout,
+		}
+	}
+}
+
+func (d timeoutDownloader) Download(ctx context.Context, url string) (*Media, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout)
+	defer cancel()
+
+	return d.IDownloader.Download(ctx, url)
+}
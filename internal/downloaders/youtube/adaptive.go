@@ -0,0 +1,214 @@
+package youtube
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/StounhandJ/shorts_forward/internal/cache"
+	"github.com/StounhandJ/shorts_forward/internal/mp4"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/kkdai/youtube/v2"
+)
+
+const (
+	defaultAdaptiveSize = 64 << 20
+	// mergedCacheBytes предел памяти под склеенные ролики, которые держатся, пока ТГ забирает их частями
+	mergedCacheBytes = 128 << 20
+	// maxMerges одновременных склеек: каждая держит в памяти оба потока и результат
+	maxMerges = 2
+)
+
+// Adaptive склейка отдельных потоков видео и звука в один MP4. Форматы со звуком
+// YouTube отдаёт не выше 360p, адаптивные - в 720p/1080p, но видео и звук у них отдельно
+type Adaptive struct {
+	Enabled bool
+	// MaxHeight наибольшее качество: 720, 1080 (у вертикальных роликов это ширина кадра), 0 - без ограничения
+	MaxHeight int
+	// MaxSize предел суммарного размера потоков в байтах, склейка идёт в памяти
+	MaxSize int64
+}
+
+// formats лучшее видео без звука и лучший звук в MP4, которые вместе не больше MaxSize
+func (a Adaptive) formats(list youtube.FormatList) (*youtube.Format, *youtube.Format, bool) {
+	var audio *youtube.Format
+
+	for i, f := range list {
+		if !strings.HasPrefix(f.MimeType, "audio/mp4") || !strings.HasPrefix(codecs(f.MimeType), "mp4a") || f.ContentLength <= 0 {
+			continue
+		}
+
+		if audio == nil || f.Bitrate > audio.Bitrate {
+			audio = &list[i]
+		}
+	}
+
+	if audio == nil {
+		return nil, nil, false
+	}
+
+	var videos []*youtube.Format
+
+	for i, f := range list {
+		// ТГ не везде показывает VP9 и AV1, поэтому только H.264
+		if !strings.HasPrefix(f.MimeType, "video/mp4") || !strings.HasPrefix(codecs(f.MimeType), "avc1") ||
+			f.AudioChannels > 0 || f.ContentLength <= 0 {
+			continue
+		}
+
+		if a.MaxHeight > 0 && min(f.Width, f.Height) > a.MaxHeight {
+			continue
+		}
+
+		videos = append(videos, &list[i])
+	}
+
+	slices.SortFunc(videos, func(x, y *youtube.Format) int {
+		return cmp.Or(cmp.Compare(y.Width*y.Height, x.Width*x.Height), cmp.Compare(y.Bitrate, x.Bitrate))
+	})
+
+	for _, v := range videos {
+		if v.ContentLength+audio.ContentLength <= a.maxSize() {
+			return v, audio, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+func (a Adaptive) maxSize() int64 {
+	return cmp.Or(a.MaxSize, defaultAdaptiveSize)
+}
+
+// merger скачивает потоки видео и звука и склеивает их. Результат кэшируется,
+// потому что ТГ забирает файл несколькими запросами с Range.
+// Память ограничена: в кэше не больше mergedCacheBytes, одновременно идёт не больше maxMerges склеек
+type merger struct {
+	cache    *cache.LRU[[]byte]
+	inflight cache.Flight[[]byte]
+	slots    chan struct{}
+}
+
+func newMerger(maxSize int64) *merger {
+	return &merger{
+		cache: cache.NewLRU[[]byte](int(max(mergedCacheBytes/maxSize, 1)), resolveTTL),
+		slots: make(chan struct{}, maxMerges),
+	}
+}
+
+// merged склеенный ролик из форматов video и audio
+func (d downloader) merged(ctx context.Context, res *resolution, video, audio *youtube.Format) ([]byte, error) {
+	key := mergedKey(res.video.ID, video, audio)
+
+	if data, ok := d.merger.cache.Get(key); ok {
+		return data, nil
+	}
+
+	data, _, err := d.merger.inflight.Do(ctx, key, func(ctx context.Context) ([]byte, error) {
+		select {
+		case d.merger.slots <- struct{}{}:
+			defer func() { <-d.merger.slots }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		utils.Metrics.Add("youtube_adaptive_merge", 1)
+
+		var (
+			wg                   sync.WaitGroup
+			videoData, audioData []byte
+			videoErr, audioErr   error
+		)
+
+		wg.Go(func() { videoData, videoErr = d.download(ctx, res, video) })
+		wg.Go(func() { audioData, audioErr = d.download(ctx, res, audio) })
+		wg.Wait()
+
+		if err := cmp.Or(videoErr, audioErr); err != nil {
+			return nil, err
+		}
+
+		data, err := remux(videoData, audioData)
+		if err != nil {
+			return nil, err
+		}
+
+		d.merger.cache.Set(key, data)
+
+		return data, nil
+	})
+
+	return data, err
+}
+
+func mergedKey(id string, video, audio *youtube.Format) string {
+	return fmt.Sprintf("%s/%d/%d", id, video.ItagNo, audio.ItagNo)
+}
+
+// download поток формата целиком
+func (d downloader) download(ctx context.Context, res *resolution, format *youtube.Format) ([]byte, error) {
+	url, err := d.resolver.streamURL(ctx, res, format)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := d.openStream(ctx, url, 0, format.ContentLength-1)
+	if err != nil {
+		return nil, err
+	}
+	defer closeBody(body)
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("поток itag %d: %w", format.ItagNo, err)
+	}
+
+	if int64(len(data)) != format.ContentLength {
+		return nil, fmt.Errorf("поток itag %d: получено %d байт из %d", format.ItagNo, len(data), format.ContentLength)
+	}
+
+	return data, nil
+}
+
+// remux первая видеодорожка из videoData и первая звуковая из audioData в одном MP4
+func remux(videoData, audioData []byte) ([]byte, error) {
+	video, err := firstTrack(videoData, "vide")
+	if err != nil {
+		return nil, fmt.Errorf("видео: %w", err)
+	}
+
+	audio, err := firstTrack(audioData, "soun")
+	if err != nil {
+		return nil, fmt.Errorf("звук: %w", err)
+	}
+
+	var out bytes.Buffer
+
+	out.Grow(len(videoData) + len(audioData))
+
+	if err := mp4.Write(&out, video, audio); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+func firstTrack(file []byte, handler string) (mp4.Track, error) {
+	tracks, err := mp4.ReadTracks(file)
+	if err != nil {
+		return mp4.Track{}, err
+	}
+
+	for _, t := range tracks {
+		if t.Handler == handler {
+			return t, nil
+		}
+	}
+
+	return mp4.Track{}, fmt.Errorf("%w: нет дорожки %s", mp4.ErrInvalid, handler)
+}
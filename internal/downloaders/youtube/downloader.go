@@ -1,55 +1,126 @@
 package youtube
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"mime"
 	"net/http"
-	"strings"
 
 	"github.com/StounhandJ/shorts_forward/internal/downloaders"
 	"github.com/kkdai/youtube/v2"
 )
 
 type downloader struct {
-	client *youtube.Client
-	domain string
+	// httpClient для запросов к потокам через прокси
+	httpClient *http.Client
+	resolver   *resolver
+	domain     string
+	signing    Signing
+	adaptive   Adaptive
+	merger     *merger
 }
 
-func New(client *http.Client, domain string) *downloader {
+func New(client *http.Client, domain string, signing Signing, adaptive Adaptive) *downloader {
 	return &downloader{
-		client: &youtube.Client{
+		httpClient: client,
+		resolver: newResolver(&youtube.Client{
 			HTTPClient: client,
-		},
-		domain: domain,
+		}),
+		domain:   domain,
+		signing:  signing,
+		adaptive: adaptive,
+		merger:   newMerger(adaptive.maxSize()),
 	}
 }
 
-func (d downloader) Download(url string) (*downloaders.Video, error) {
-	youtubeVideo, err := d.client.GetVideo(url)
+func (d downloader) Download(ctx context.Context, url string) (*downloaders.Media, error) {
+	// Без секрета прокси не принимает ссылки, выдавать их незачем
+	if err := d.signing.Validate(); err != nil {
+		return nil, err
+	}
+
+	id, err := parseVideoID(url)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := d.resolver.resolve(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	youtubeVideo := res.video
+
+	// Прямые эфиры и премьеры отдаются только HLS потоком
 	formats := youtubeVideo.Formats.WithAudioChannels().Type("video/mp4")
 	if len(formats) == 0 {
-		return nil, errors.New("не найдено VideoURL")
+		return nil, fmt.Errorf("%w: не найдено VideoURL", downloaders.ErrUnsupportedContent)
 	}
 
 	if len(youtubeVideo.Thumbnails) == 0 {
 		return nil, errors.New("не найдено ThumbnailURL")
 	}
 
-	return &downloaders.Video{
-		Title:        youtubeVideo.Title,
-		VideoURL:     fmt.Sprintf("%s/video?src=%s", d.domain, url),
-		ThumbnailURL: youtubeVideo.Thumbnails[len(youtubeVideo.Thumbnails)-1].URL,
-		MimeType:     "video/mp4",
-		ViewCount:    youtubeVideo.Views,
-		LikeCount:    0, // Нельзя получить через API
-		Duration:     int(youtubeVideo.Duration / 1000000000),
+	// ТГ не может скачать с CDN ютуба, поэтому все варианты отдаются через наш прокси
+	renditions := make([]downloaders.Rendition, 0, len(formats))
+	for _, f := range formats {
+		renditions = append(renditions, downloaders.Rendition{
+			URL:      d.videoURL(id, fmt.Sprintf("itag=%d", f.ItagNo)),
+			MimeType: "video/mp4",
+			Width:    f.Width,
+			Height:   f.Height,
+			Bitrate:  f.Bitrate,
+			Size:     f.ContentLength,
+			Codec:    codecs(f.MimeType),
+			HasAudio: true,
+		})
+	}
+
+	if video, audio, ok := d.adaptive.formats(youtubeVideo.Formats); d.adaptive.Enabled && ok {
+		// Размер примерный: к потокам добавляется заголовок склеенного файла
+		renditions = append(renditions, downloaders.Rendition{
+			URL:      d.videoURL(id, fmt.Sprintf("itag=%d&audio=%d", video.ItagNo, audio.ItagNo)),
+			MimeType: "video/mp4",
+			Width:    video.Width,
+			Height:   video.Height,
+			Bitrate:  video.Bitrate + audio.Bitrate,
+			Size:     video.ContentLength + audio.ContentLength,
+			Codec:    codecs(video.MimeType) + ", " + codecs(audio.MimeType),
+			HasAudio: true,
+		})
+	}
+
+	return &downloaders.Media{
+		Title:      youtubeVideo.Title,
+		ViewCount:  youtubeVideo.Views,
+		LikeCount:  0, // Нельзя получить через API
+		UploadedAt: youtubeVideo.PublishDate,
+		Author: downloaders.Author{
+			ID:       youtubeVideo.ChannelID,
+			Username: youtubeVideo.ChannelHandle,
+			Name:     youtubeVideo.Author,
+		},
+		Items: []downloaders.Item{{
+			Type:         downloaders.MediaTypeVideo,
+			ThumbnailURL: youtubeVideo.Thumbnails[len(youtubeVideo.Thumbnails)-1].URL,
+			Duration:     int(youtubeVideo.Duration / 1000000000),
+			Renditions:   renditions,
+		}},
 	}, nil
 }
 
-func (downloader) Valid(url string) bool {
-	return strings.Contains(url, "youtube.com/")
+// videoURL подписанная ссылка на ролик через наш прокси
+func (d downloader) videoURL(id, params string) string {
+	return fmt.Sprintf("%s/video?id=%s&%s&%s", d.domain, id, params, d.signing.query(id))
+}
+
+// codecs достаёт кодеки из mime типа формата: video/mp4; codecs="avc1.42001E, mp4a.40.2"
+func codecs(mimeType string) string {
+	_, params, err := mime.ParseMediaType(mimeType)
+	if err != nil {
+		return ""
+	}
+
+	return params["codecs"]
 }
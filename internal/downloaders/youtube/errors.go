@@ -0,0 +1,62 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	netUrl "net/url"
+	"slices"
+	"strings"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/kkdai/youtube/v2"
+)
+
+// mapError приводит ошибки библиотеки youtube к категориям downloaders
+func mapError(err error) error {
+	var (
+		playability *youtube.ErrPlayabiltyStatus
+		status      youtube.ErrUnexpectedStatusCode
+		urlErr      *netUrl.Error
+	)
+
+	switch {
+	case errors.Is(err, youtube.ErrVideoPrivate):
+		return fmt.Errorf("%w: %w", downloaders.ErrPrivate, err)
+	case errors.Is(err, youtube.ErrLoginRequired):
+		return fmt.Errorf("%w: %w", downloaders.ErrAgeRestricted, err)
+	case errors.Is(err, youtube.ErrInvalidCharactersInVideoID), errors.Is(err, youtube.ErrVideoIDMinLength):
+		return fmt.Errorf("%w: %w", downloaders.ErrNotFound, err)
+	case errors.As(err, &playability) && playabilityError(playability) != nil:
+		return fmt.Errorf("%w: %w", playabilityError(playability), err)
+	case errors.As(err, &status):
+		return downloaders.StatusError(int(status))
+	case errors.As(err, &urlErr):
+		return downloaders.Temporary(err)
+	default:
+		return err
+	}
+}
+
+// ageReasons фразы причины, которыми плеер сообщает о возрастном ограничении
+var ageReasons = []string{"confirm your age", "age-restricted", "age restricted", "inappropriate for some users"}
+
+// playabilityError категория по статусу воспроизведения из ответа плеера, nil если статус неизвестен
+func playabilityError(status *youtube.ErrPlayabiltyStatus) error {
+	reason := strings.ToLower(status.Reason)
+
+	switch {
+	case strings.Contains(reason, "country"):
+		return downloaders.ErrGeoBlocked
+	case status.Status == "AGE_CHECK_REQUIRED", status.Status == "AGE_VERIFICATION_REQUIRED",
+		status.Status == "CONTENT_CHECK_REQUIRED", slices.ContainsFunc(ageReasons, func(s string) bool { return strings.Contains(reason, s) }):
+		return downloaders.ErrAgeRestricted
+	case status.Status == "LOGIN_REQUIRED", strings.Contains(reason, "private"):
+		return downloaders.ErrPrivate
+	case status.Status == "ERROR":
+		return downloaders.ErrNotFound
+	case status.Status == "LIVE_STREAM_OFFLINE":
+		return downloaders.ErrUnsupportedContent
+	default:
+		return nil
+	}
+}
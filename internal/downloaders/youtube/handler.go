@@ -1,20 +1,25 @@
 package youtube
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"net/url"
 	"strconv"
-	"strings"
+	"time"
 
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/kkdai/youtube/v2"
 	"github.com/valyala/fasthttp"
 )
 
+// proxyTimeout сколько запрос к прокси ждёт ответа плеера и склейки потоков
+const proxyTimeout = 2 * time.Minute
+
 func (d downloader) Handler(ctx *fasthttp.RequestCtx) {
-	// получаем параметр src
-	src := string(ctx.QueryArgs().Peek("src"))
+	src := string(ctx.QueryArgs().Peek("id"))
 	if src == "" {
 		ctx.Response.Header.Set("Content-Type", "image/webp")
 		ctx.Response.Header.Set("Content-Disposition", "inline")
@@ -23,160 +28,162 @@ func (d downloader) Handler(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	targetURL, err := url.Parse(src)
+	id, err := parseVideoID(src)
 	if err != nil {
-		ctx.Error("invalid src", http.StatusBadRequest)
+		ctx.Error("invalid id", http.StatusBadRequest)
 		return
 	}
 
-	ctx.Response.Header.Add("Content-Type", "video/mp4")
-	ctx.Response.Header.Set("Content-Disposition", `inline; filename="ffffe11cdc4.mp4"`)
-	ctx.Response.Header.Set("Accept-Ranges", "bytes")
-
-	youtubeVideo, err := d.client.GetVideo(targetURL.String())
+	// Ссылки выдаёт только Download, остальные запросы - попытка пользоваться нами как прокси
+	err = d.signing.verify(id, string(ctx.QueryArgs().Peek("exp")), string(ctx.QueryArgs().Peek("sig")))
 	if err != nil {
-		ctx.Error("error get video", http.StatusBadGateway)
-		return
-	}
+		utils.Metrics.Add("youtube_proxy_forbidden", 1)
+		utils.Log.Debugf("youtube прокси %s: %s", id, err)
+		ctx.Error("forbidden", http.StatusForbidden)
 
-	formats := youtubeVideo.Formats.WithAudioChannels().Type("video/mp4")
-	if len(formats) == 0 {
-		ctx.Error("not found video", http.StatusBadGateway)
 		return
 	}
 
-	videoReader, contentLength, err := d.client.GetStream(youtubeVideo, &formats[0])
-	if err != nil {
-		ctx.Error("get video stream", http.StatusBadGateway)
-		return
-	}
+	if !ctx.IsGet() && !ctx.IsHead() {
+		ctx.Error("method not allowed", http.StatusMethodNotAllowed)
+		ctx.Response.Header.Set("Allow", "GET, HEAD")
 
-	rangeHdr := string(ctx.Request.Header.Peek("Range"))
-	if rangeHdr == "" {
-		// нет Range - отдаем весь файл
-		ctx.Response.Header.Set("Content-Length", strconv.FormatInt(contentLength, 10))
-		// Оборачиваем в readCloserOnEOF чтобы закрыть после EOF
-		rc := &readCloserOnEOF{r: videoReader, c: videoReader}
-		// fasthttp.SetBodyStream принимает io.Reader и int (size)
-		ctx.SetBodyStream(rc, int(contentLength))
 		return
 	}
 
-	// Парсим Range
-	start, end, err := parseRange(rangeHdr, contentLength)
+	ctx.Response.Header.Add("Content-Type", "video/mp4")
+	ctx.Response.Header.Set("Content-Disposition", `inline; filename="ffffe11cdc4.mp4"`)
+
+	// RequestCtx не отменяется при обрыве соединения и переиспользуется после ответа,
+	// поэтому общие для всех запросов ответ плеера и склейка получают свой контекст
+	apiCtx, cancel := context.WithTimeout(context.Background(), proxyTimeout)
+	defer cancel()
+
+	res, err := d.resolver.resolve(apiCtx, id)
 	if err != nil {
-		// 416 Range Not Satisfiable
-		ctx.SetStatusCode(fasthttp.StatusRequestedRangeNotSatisfiable)
-		// обязательный заголовок для 416:
-		ctx.Response.Header.Set("Content-Range", fmt.Sprintf("bytes */%d", contentLength))
-		_ = videoReader.Close()
+		ctx.Error("error get video", http.StatusBadGateway)
 		return
 	}
 
-	length := end - start + 1
-	// Установим заголовки частичного контента
-	ctx.SetStatusCode(fasthttp.StatusPartialContent) // 206
-	ctx.Response.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, contentLength))
-	ctx.Response.Header.Set("Content-Length", strconv.FormatInt(length, 10))
+	var c content
 
-	// Попытаемся использовать Seek, если поддерживается
-	if seeker, ok := interface{}(videoReader).(io.Seeker); ok {
-		_, seekErr := seeker.Seek(start, io.SeekStart)
-		if seekErr != nil {
-			// fallback: читаем и отбросим
-			_, _ = io.CopyN(io.Discard, videoReader, start)
+	// Ролик, склеенный из адаптивных потоков. При выключенной склейке отдаётся формат со звуком
+	if audioItag, err := ctx.QueryArgs().GetUint("audio"); err == nil && d.adaptive.Enabled {
+		c, err = d.adaptiveContent(apiCtx, ctx, id, res, audioItag)
+		if err != nil {
+			ctx.Error("get video stream", http.StatusBadGateway)
+			return
 		}
-		limited := io.LimitReader(videoReader, length)
-		rc := &readCloserOnEOF{r: limited, c: videoReader}
-		ctx.SetBodyStream(rc, int(length))
-		return
-	}
-
-	// Если Seek не поддерживается - прочитаем и отбросим первые start байт (медленнее)
-	if start > 0 {
-		if _, err := io.CopyN(io.Discard, videoReader, start); err != nil {
-			_ = videoReader.Close()
-			ctx.Error("failed to skip bytes", fasthttp.StatusBadGateway)
+	} else {
+		formats := res.video.Formats.WithAudioChannels().Type("video/mp4")
+		if len(formats) == 0 {
+			ctx.Error("not found video", http.StatusBadGateway)
 			return
 		}
+
+		// itag выбранного варианта, по умолчанию первый подходящий формат
+		format := &formats[0]
+		if f := formats.Itag(ctx.QueryArgs().GetUintOrZero("itag")); len(f) > 0 {
+			format = &f[0]
+		}
+
+		c = d.formatContent(id, res, format)
 	}
-	limited := io.LimitReader(videoReader, length)
-	rc := &readCloserOnEOF{r: limited, c: videoReader}
-	ctx.SetBodyStream(rc, int(length))
+
+	serve(ctx, c)
 }
 
-// parseRange поддерживает одиночную запись типа:
-// Range: bytes=START-END
-// Range: bytes=START-
-// Range: bytes=-SUFFIXLEN
-func parseRange(s string, size int64) (start, end int64, err error) {
-	const prefix = "bytes="
-	if !strings.HasPrefix(s, prefix) {
-		return 0, 0, errors.New("invalid range")
-	}
-	r := strings.TrimSpace(s[len(prefix):])
-	if r == "" {
-		return 0, 0, errors.New("empty range")
-	}
-
-	if strings.HasPrefix(r, "-") {
-		// suffix: -N  => last N bytes
-		n, err := strconv.ParseInt(r[1:], 10, 64)
-		if err != nil || n <= 0 {
-			return 0, 0, errors.New("invalid suffix range")
-		}
-		if n > size {
-			n = size
+// formatContent поток формата с CDN. Поток открывается, только когда нужно тело ответа
+func (d downloader) formatContent(id string, res *resolution, format *youtube.Format) content {
+	c := content{
+		size:     format.ContentLength,
+		modified: lastModified(format.LastModified),
+	}
+
+	if format.ContentLength <= 0 {
+		c.size = -1
+	}
+
+	if format.LastModified != "" {
+		c.etag = fmt.Sprintf(`"%s-%d-%s"`, id, format.ItagNo, format.LastModified)
+	}
+
+	c.open = func(start, end int64) (io.ReadCloser, error) {
+		// Запрос к CDN живёт, пока fasthttp не закроет тело ответа: после отправки или при обрыве соединения
+		streamCtx, cancel := context.WithCancel(context.Background())
+
+		streamURL, err := d.resolver.streamURL(streamCtx, res, format)
+		if err == nil {
+			var body io.ReadCloser
+
+			body, err = d.openStream(streamCtx, streamURL, start, end)
+			if err == nil {
+				return &stream{Reader: body, Closer: cancelOnClose{Closer: body, cancel: cancel}}, nil
+			}
 		}
-		start = size - n
-		end = size - 1
-		return start, end, nil
+
+		cancel()
+
+		// Ссылка на поток устарела - следующий запрос заново спросит плеер
+		d.resolver.forget(id)
+		utils.Log.Warnf("youtube %s itag %d: %s", id, format.ItagNo, err)
+
+		return nil, err
 	}
 
-	parts := strings.SplitN(r, "-", 2)
-	if len(parts) != 2 {
-		return 0, 0, errors.New("invalid range format")
+	return c
+}
+
+// adaptiveContent ролик, склеенный из видео itag и звука audioItag. На HEAD склейка не запускается:
+// если ролика ещё нет в кэше, размер в ответе не указывается
+func (d downloader) adaptiveContent(apiCtx context.Context, ctx *fasthttp.RequestCtx, id string, res *resolution, audioItag int) (content, error) {
+	video := res.video.Formats.Itag(ctx.QueryArgs().GetUintOrZero("itag"))
+	audio := res.video.Formats.Itag(audioItag)
+
+	if len(video) == 0 || len(audio) == 0 || video[0].ContentLength+audio[0].ContentLength > d.adaptive.maxSize() {
+		return content{}, errors.New("нет форматов для склейки")
 	}
 
-	if parts[0] == "" {
-		return 0, 0, errors.New("invalid start")
+	c := content{size: -1, modified: lastModified(video[0].LastModified)}
+	if modified := lastModified(audio[0].LastModified); modified.After(c.modified) {
+		c.modified = modified
 	}
-	s0, err := strconv.ParseInt(parts[0], 10, 64)
-	if err != nil || s0 < 0 {
-		return 0, 0, errors.New("invalid start")
+
+	if video[0].LastModified != "" && audio[0].LastModified != "" {
+		c.etag = fmt.Sprintf(`"%s-%d-%d-%s-%s"`, id, video[0].ItagNo, audio[0].ItagNo, video[0].LastModified, audio[0].LastModified)
 	}
-	start = s0
 
-	if parts[1] == "" {
-		// START-
-		end = size - 1
-	} else {
-		e0, err := strconv.ParseInt(parts[1], 10, 64)
-		if err != nil || e0 < 0 {
-			return 0, 0, errors.New("invalid end")
+	data, ok := d.merger.cache.Get(mergedKey(id, &video[0], &audio[0]))
+	if !ok && !ctx.IsHead() && !c.notModified(&ctx.Request.Header) {
+		var err error
+
+		data, err = d.merged(apiCtx, res, &video[0], &audio[0])
+		if err != nil {
+			d.resolver.forget(id)
+			utils.Log.Warnf("youtube %s itag %d+%d: %s", id, video[0].ItagNo, audio[0].ItagNo, err)
+
+			return content{}, err
 		}
-		end = e0
-	}
 
-	if start > end || start >= size {
-		return 0, 0, errors.New("range unsatisfiable")
+		ok = true
 	}
-	if end >= size {
-		end = size - 1
+
+	if ok {
+		c.size = int64(len(data))
+		c.open = func(start, end int64) (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(data[start : end+1])), nil
+		}
 	}
-	return start, end, nil
-}
 
-// обёртка: закроет c при достижении EOF
-type readCloserOnEOF struct {
-	r io.Reader
-	c io.Closer
+	return c, nil
 }
 
-func (r *readCloserOnEOF) Read(p []byte) (int, error) {
-	n, err := r.r.Read(p)
-	if err == io.EOF && r.c != nil {
-		_ = r.c.Close()
+// lastModified время изменения формата из ответа плеера (микросекунды), нулевое если его нет
+func lastModified(micros string) time.Time {
+	v, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil || v <= 0 {
+		return time.Time{}
 	}
-	return n, err
+
+	return time.UnixMicro(v)
 }
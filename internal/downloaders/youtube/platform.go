@@ -0,0 +1,80 @@
+package youtube
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+)
+
+var videoIDRe = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+var Platform = downloaders.Platform{
+	Name:         "youtube",
+	Hosts:        []string{"*.youtube.com", "youtu.be", "www.youtu.be", "*.youtube-nocookie.com"},
+	Canonicalize: canonicalize,
+}
+
+// canonicalize ссылка на ролик вида https://www.youtube.com/watch?v=ID без параметров отслеживания (si, feature)
+func canonicalize(u *url.URL) (string, string, error) {
+	id, err := videoID(u)
+	if err != nil {
+		return "", "", err
+	}
+
+	return watchURL(id), id, nil
+}
+
+func watchURL(id string) string {
+	return "https://www.youtube.com/watch?v=" + id
+}
+
+// videoID достаёт ID ролика из любой публичной ссылки:
+// youtu.be/ID, /watch?v=ID, /shorts/ID, /live/ID, /embed/ID, /v/ID и /attribution_link?u=/watch?v=ID
+// на youtube.com, m.youtube.com, music.youtube.com и youtube-nocookie.com
+func videoID(u *url.URL) (string, error) {
+	var id string
+
+	segments := downloaders.PathSegments(u)
+
+	switch {
+	case strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") == "youtu.be":
+		if len(segments) > 0 {
+			id = segments[0]
+		}
+	case len(segments) == 1 && segments[0] == "watch":
+		id = u.Query().Get("v")
+	case len(segments) >= 2 && (segments[0] == "shorts" || segments[0] == "live" || segments[0] == "embed" ||
+		segments[0] == "v" || segments[0] == "e" || segments[0] == "watch"):
+		id = segments[1]
+	case len(segments) == 1 && segments[0] == "attribution_link":
+		next, err := url.Parse(u.Query().Get("u"))
+		if err != nil || next.IsAbs() {
+			return "", downloaders.ErrNotMediaURL
+		}
+
+		return videoID(next)
+	}
+
+	if !videoIDRe.MatchString(id) {
+		return "", downloaders.ErrNotMediaURL
+	}
+
+	return id, nil
+}
+
+// parseVideoID ID ролика из ссылки либо сам ID
+func parseVideoID(s string) (string, error) {
+	if videoIDRe.MatchString(s) {
+		return s, nil
+	}
+
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", downloaders.ErrNotMediaURL, err)
+	}
+
+	return videoID(u)
+}
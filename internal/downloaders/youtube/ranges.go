@@ -0,0 +1,229 @@
+package youtube
+
+import (
+	"cmp"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxRanges больше диапазонов в одном запросе не разбираем - отдаём весь файл
+const maxRanges = 16
+
+var (
+	errInvalidRange  = errors.New("некорректный Range")
+	errUnsatisfiable = errors.New("диапазон за пределами файла")
+)
+
+// byteRange байты start..end включительно
+type byteRange struct {
+	start, end int64
+}
+
+func (r byteRange) length() int64 {
+	return r.end - r.start + 1
+}
+
+func (r byteRange) contentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.start, r.end, size)
+}
+
+// parseRanges разбирает Range по RFC 9110 14.1.2:
+// bytes=0-499, bytes=500-, bytes=-500 и несколько диапазонов через запятую.
+// Недостижимые диапазоны отбрасываются, пересекающиеся и соседние склеиваются.
+// errInvalidRange - заголовок надо игнорировать, errUnsatisfiable - ответ 416
+func parseRanges(value string, size int64) ([]byteRange, error) {
+	unit, set, ok := strings.Cut(value, "=")
+	if !ok || !strings.EqualFold(strings.TrimSpace(unit), "bytes") {
+		return nil, errInvalidRange
+	}
+
+	var ranges []byteRange
+
+	specs := 0
+
+	for spec := range strings.SplitSeq(set, ",") {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		if specs++; specs > maxRanges {
+			return nil, errInvalidRange
+		}
+
+		first, last, ok := strings.Cut(spec, "-")
+		if !ok {
+			return nil, errInvalidRange
+		}
+
+		if first == "" {
+			// Последние N байт
+			n, err := parsePos(last)
+			if err != nil {
+				return nil, err
+			}
+
+			if n > 0 && size > 0 {
+				ranges = append(ranges, byteRange{start: size - min(n, size), end: size - 1})
+			}
+
+			continue
+		}
+
+		start, err := parsePos(first)
+		if err != nil {
+			return nil, err
+		}
+
+		end := size - 1
+
+		if last != "" {
+			if end, err = parsePos(last); err != nil || end < start {
+				return nil, errInvalidRange
+			}
+		}
+
+		if start < size {
+			ranges = append(ranges, byteRange{start: start, end: min(end, size-1)})
+		}
+	}
+
+	if specs == 0 {
+		return nil, errInvalidRange
+	}
+
+	if len(ranges) == 0 {
+		return nil, errUnsatisfiable
+	}
+
+	return coalesce(ranges), nil
+}
+
+// parsePos неотрицательное число из одних цифр, без знаков и пробелов
+func parsePos(s string) (int64, error) {
+	if s == "" || strings.TrimLeft(s, "0123456789") != "" {
+		return 0, errInvalidRange
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, errInvalidRange
+	}
+
+	return n, nil
+}
+
+// coalesce склеивает пересекающиеся и соседние диапазоны, чтобы не отдавать одни байты дважды
+func coalesce(ranges []byteRange) []byteRange {
+	slices.SortFunc(ranges, func(a, b byteRange) int { return cmp.Compare(a.start, b.start) })
+
+	result := ranges[:1]
+
+	for _, r := range ranges[1:] {
+		last := &result[len(result)-1]
+		if r.start <= last.end+1 {
+			last.end = max(last.end, r.end)
+
+			continue
+		}
+
+		result = append(result, r)
+	}
+
+	return result
+}
+
+// multipart тело ответа multipart/byteranges. Байты каждой части открываются,
+// когда до неё доходит чтение, и закрываются, когда часть дочитана
+type multipart struct {
+	io.Reader
+
+	boundary string
+	length   int64
+	parts    []*lazyStream
+}
+
+func newMultipart(ranges []byteRange, c content, partType string) *multipart {
+	m := &multipart{boundary: rand.Text()}
+
+	readers := make([]io.Reader, 0, 2*len(ranges)+1)
+
+	for i, r := range ranges {
+		header := fmt.Sprintf("--%s\r\nContent-Type: %s\r\nContent-Range: %s\r\n\r\n", m.boundary, partType, r.contentRange(c.size))
+		if i > 0 {
+			header = "\r\n" + header
+		}
+
+		part := &lazyStream{open: func() (io.ReadCloser, error) { return c.open(r.start, r.end) }}
+
+		m.parts = append(m.parts, part)
+		m.length += int64(len(header)) + r.length()
+		readers = append(readers, strings.NewReader(header), part)
+	}
+
+	trailer := "\r\n--" + m.boundary + "--\r\n"
+	m.length += int64(len(trailer))
+	m.Reader = io.MultiReader(append(readers, strings.NewReader(trailer))...)
+
+	return m
+}
+
+// Close закрывает открытую часть, если ответ оборвался на середине
+func (m *multipart) Close() error {
+	var errs []error
+
+	for _, part := range m.parts {
+		errs = append(errs, part.Close())
+	}
+
+	return errors.Join(errs...)
+}
+
+// lazyStream поток, который открывается при первом чтении
+type lazyStream struct {
+	open func() (io.ReadCloser, error)
+	body io.ReadCloser
+	done bool
+}
+
+func (l *lazyStream) Read(p []byte) (int, error) {
+	if l.done {
+		return 0, io.EOF
+	}
+
+	if l.body == nil {
+		body, err := l.open()
+		if err != nil {
+			return 0, err
+		}
+
+		l.body = body
+	}
+
+	n, err := l.body.Read(p)
+	if errors.Is(err, io.EOF) {
+		l.done = true
+
+		if closeErr := l.Close(); closeErr != nil {
+			return n, closeErr
+		}
+	}
+
+	return n, err
+}
+
+func (l *lazyStream) Close() error {
+	if l.body == nil {
+		return nil
+	}
+
+	body := l.body
+	l.body = nil
+
+	return body.Close()
+}
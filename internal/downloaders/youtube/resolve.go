@@ -0,0 +1,95 @@
+package youtube
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/cache"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/kkdai/youtube/v2"
+)
+
+const (
+	// resolveTTL ссылки на потоки живут несколько часов, но ТГ забирает файл в первые минуты после ответа
+	resolveTTL       = 10 * time.Minute
+	resolveCacheSize = 500
+)
+
+// resolution ответ плеера на ролик и уже полученные ссылки на потоки
+type resolution struct {
+	video *youtube.Video
+
+	mu      sync.Mutex
+	streams map[int]string // itag → ссылка на поток
+}
+
+// resolver кэширует ответ плеера по ID ролика, чтобы inline ответ и все запросы ТГ к прокси
+// обходились одним обращением к плееру. Одновременные запросы одного ролика схлопываются в один
+type resolver struct {
+	client   *youtube.Client
+	cache    *cache.LRU[*resolution]
+	inflight cache.Flight[*resolution]
+}
+
+func newResolver(client *youtube.Client) *resolver {
+	return &resolver{
+		client: client,
+		cache:  cache.NewLRU[*resolution](resolveCacheSize, resolveTTL),
+	}
+}
+
+// resolve ответ плеера на ролик из кэша или от YouTube. Ошибки не кэшируются
+func (r *resolver) resolve(ctx context.Context, id string) (*resolution, error) {
+	if res, ok := r.cache.Get(id); ok {
+		utils.Metrics.Add("youtube_resolve_hit", 1)
+
+		return res, nil
+	}
+
+	res, shared, err := r.inflight.Do(ctx, id, func(ctx context.Context) (*resolution, error) {
+		utils.Metrics.Add("youtube_resolve_miss", 1)
+
+		video, err := r.client.GetVideoContext(ctx, id)
+		if err != nil {
+			return nil, mapError(err)
+		}
+
+		res := &resolution{
+			video:   video,
+			streams: make(map[int]string),
+		}
+		r.cache.Set(id, res)
+
+		return res, nil
+	})
+	if shared {
+		utils.Metrics.Add("youtube_resolve_shared", 1)
+	}
+
+	return res, err
+}
+
+// forget убирает ролик из кэша, например когда ссылка на поток перестала открываться
+func (r *resolver) forget(id string) {
+	r.cache.Delete(id)
+}
+
+// streamURL ссылка на поток формата, получается один раз на формат
+func (r *resolver) streamURL(ctx context.Context, res *resolution, format *youtube.Format) (string, error) {
+	res.mu.Lock()
+	defer res.mu.Unlock()
+
+	if url, ok := res.streams[format.ItagNo]; ok {
+		return url, nil
+	}
+
+	url, err := r.client.GetStreamURLContext(ctx, res.video, format)
+	if err != nil {
+		return "", err
+	}
+
+	res.streams[format.ItagNo] = url
+
+	return url, nil
+}
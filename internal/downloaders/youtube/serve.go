@@ -0,0 +1,194 @@
+package youtube
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/valyala/fasthttp"
+)
+
+// content тело ответа прокси: поток формата с CDN или склеенный ролик
+type content struct {
+	// size размер в байтах, -1 - неизвестен (Range не поддерживается)
+	size int64
+	// etag и modified валидаторы для условных запросов, пустые - неизвестны
+	etag     string
+	modified time.Time
+	// open открывает байты start..end, при неизвестном размере - всё тело (0, -1).
+	// nil - тело недоступно без обращения к YouTube, так бывает только на HEAD
+	open func(start, end int64) (io.ReadCloser, error)
+}
+
+// serve отвечает на GET и HEAD по RFC 9110: условные запросы, Range, If-Range и multipart/byteranges.
+// Поток открывается только после того, как ответ понятен: на HEAD, 304, 412 и 416 его не открываем
+func serve(ctx *fasthttp.RequestCtx, c content) {
+	header := &ctx.Response.Header
+
+	if c.etag != "" {
+		header.Set("ETag", c.etag)
+	}
+
+	if !c.modified.IsZero() {
+		header.Set("Last-Modified", c.modified.UTC().Format(http.TimeFormat))
+	}
+
+	if c.size >= 0 || c.open == nil {
+		header.Set("Accept-Ranges", "bytes")
+	}
+
+	if status := c.preconditions(&ctx.Request.Header); status != 0 {
+		ctx.SetStatusCode(status)
+		return
+	}
+
+	ranges, err := c.ranges(&ctx.Request.Header)
+	if errors.Is(err, errUnsatisfiable) {
+		ctx.SetStatusCode(fasthttp.StatusRequestedRangeNotSatisfiable)
+		header.Set("Content-Range", fmt.Sprintf("bytes */%d", c.size))
+
+		return
+	}
+
+	// Некорректный Range игнорируется, отдаётся весь файл (RFC 9110 14.2)
+	if err != nil {
+		ranges = nil
+	}
+
+	var (
+		open   func() (io.ReadCloser, error)
+		length int64
+	)
+
+	switch len(ranges) {
+	case 0:
+		length = c.size
+		open = func() (io.ReadCloser, error) { return c.open(0, c.size-1) }
+	case 1:
+		r := ranges[0]
+		length = r.length()
+		open = func() (io.ReadCloser, error) { return c.open(r.start, r.end) }
+
+		ctx.SetStatusCode(fasthttp.StatusPartialContent)
+		header.Set("Content-Range", r.contentRange(c.size))
+	default:
+		body := newMultipart(ranges, c, string(header.ContentType()))
+		length = body.length
+		open = func() (io.ReadCloser, error) { return body, nil }
+
+		ctx.SetStatusCode(fasthttp.StatusPartialContent)
+		header.SetContentType("multipart/byteranges; boundary=" + body.boundary)
+	}
+
+	if ctx.IsHead() || c.open == nil {
+		if length >= 0 {
+			header.SetContentLength(int(length))
+		}
+
+		return
+	}
+
+	body, err := open()
+	if err != nil {
+		ctx.Error("get video stream", http.StatusBadGateway)
+
+		return
+	}
+
+	ctx.SetBodyStream(body, int(length))
+}
+
+// preconditions статус ответа на условный запрос (RFC 9110 13.2.2), 0 - запрос выполняется
+func (c content) preconditions(h *fasthttp.RequestHeader) int {
+	if ifMatch := string(h.Peek("If-Match")); ifMatch != "" {
+		if !matchETag(ifMatch, c.etag, false) {
+			return http.StatusPreconditionFailed
+		}
+	} else if since, ok := headerTime(h, "If-Unmodified-Since"); ok && !c.modified.IsZero() &&
+		c.modified.Truncate(time.Second).After(since) {
+		return http.StatusPreconditionFailed
+	}
+
+	if c.notModified(h) {
+		return http.StatusNotModified
+	}
+
+	return 0
+}
+
+// notModified у клиента уже есть актуальная копия: If-None-Match или If-Modified-Since
+func (c content) notModified(h *fasthttp.RequestHeader) bool {
+	if ifNoneMatch := string(h.Peek("If-None-Match")); ifNoneMatch != "" {
+		return matchETag(ifNoneMatch, c.etag, true)
+	}
+
+	since, ok := headerTime(h, "If-Modified-Since")
+
+	return ok && !c.modified.IsZero() && !c.modified.Truncate(time.Second).After(since)
+}
+
+// ranges запрошенные диапазоны с учётом If-Range. nil без ошибки - отдаётся весь файл
+func (c content) ranges(h *fasthttp.RequestHeader) ([]byteRange, error) {
+	value := string(h.Peek("Range"))
+	if value == "" || c.size < 0 {
+		return nil, nil
+	}
+
+	// If-Range: диапазон только от той же версии файла, иначе весь файл заново (RFC 9110 13.1.5)
+	if ifRange := strings.TrimSpace(string(h.Peek("If-Range"))); ifRange != "" {
+		if strings.HasPrefix(ifRange, `"`) || strings.HasPrefix(ifRange, "W/") {
+			if !matchETag(ifRange, c.etag, false) {
+				return nil, nil
+			}
+		} else if date, err := http.ParseTime(ifRange); err != nil || c.modified.IsZero() ||
+			!c.modified.Truncate(time.Second).Equal(date) {
+			return nil, nil
+		}
+	}
+
+	return parseRanges(value, c.size)
+}
+
+// matchETag есть ли etag в списке из If-Match/If-None-Match. Слабое сравнение игнорирует префикс W/
+func matchETag(list, etag string, weak bool) bool {
+	// * - любая версия файла, а файл у нас есть
+	if strings.TrimSpace(list) == "*" {
+		return true
+	}
+
+	if etag == "" {
+		return false
+	}
+
+	for candidate := range strings.SplitSeq(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+
+		if strings.HasPrefix(candidate, "W/") {
+			if !weak {
+				continue
+			}
+
+			candidate = candidate[2:]
+		}
+
+		if candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+func headerTime(h *fasthttp.RequestHeader, name string) (time.Time, bool) {
+	value := string(h.Peek(name))
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	t, err := http.ParseTime(value)
+
+	return t, err == nil
+}
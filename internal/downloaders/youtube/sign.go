@@ -0,0 +1,81 @@
+package youtube
+
+import (
+	"cmp"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// defaultLinkTTL ответы загрузчика кэшируются, ссылка должна пережить кэш
+const defaultLinkTTL = 24 * time.Hour
+
+// ErrNoSecret секрет подписи не задан: подпись с пустым ключом может подделать кто угодно
+var ErrNoSecret = errors.New("не задан секрет подписи ссылок на прокси")
+
+var (
+	errUnsigned     = errors.New("ссылка без подписи")
+	errBadSignature = errors.New("неверная подпись")
+	errLinkExpired  = errors.New("ссылка устарела")
+)
+
+// Signing подпись ссылок на прокси: без неё любой, кто знает домен, может качать YouTube через нас
+type Signing struct {
+	Secret string
+	// TTL время жизни ссылки, 0 - defaultLinkTTL
+	TTL time.Duration
+}
+
+// Validate подпись без секрета не защищает прокси
+func (s Signing) Validate() error {
+	if s.Secret == "" {
+		return ErrNoSecret
+	}
+
+	return nil
+}
+
+// mac HMAC-SHA256 над ID ролика и временем истечения ссылки
+func (s Signing) mac(id string, expires int64) []byte {
+	mac := hmac.New(sha256.New, []byte(s.Secret))
+	mac.Write([]byte(id + "\n" + strconv.FormatInt(expires, 10)))
+
+	return mac.Sum(nil)
+}
+
+// query параметры exp и sig для ссылки на ролик id
+func (s Signing) query(id string) string {
+	expires := time.Now().Add(cmp.Or(s.TTL, defaultLinkTTL)).Unix()
+
+	return "exp=" + strconv.FormatInt(expires, 10) + "&sig=" + base64.RawURLEncoding.EncodeToString(s.mac(id, expires))
+}
+
+// verify проверяет подпись и срок ссылки на ролик id
+func (s Signing) verify(id, exp, sig string) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	if exp == "" || sig == "" {
+		return errUnsigned
+	}
+
+	expires, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil {
+		return errBadSignature
+	}
+
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.mac(id, expires)) {
+		return errBadSignature
+	}
+
+	if time.Now().Unix() > expires {
+		return errLinkExpired
+	}
+
+	return nil
+}
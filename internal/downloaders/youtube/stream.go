@@ -0,0 +1,77 @@
+package youtube
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+)
+
+// stream тело ответа CDN, ограниченное запрошенным диапазоном.
+// fasthttp закрывает его после отправки или при обрыве соединения
+type stream struct {
+	io.Reader
+	io.Closer
+}
+
+// cancelOnClose закрывает тело и отменяет контекст запроса, которым оно получено
+type cancelOnClose struct {
+	io.Closer
+	cancel context.CancelFunc
+}
+
+func (c cancelOnClose) Close() error {
+	defer c.cancel()
+
+	return c.Closer.Close()
+}
+
+// openStream открывает байты start..end потока. При end < start размер неизвестен, поток отдаётся целиком
+func (d downloader) openStream(ctx context.Context, url string, start, end int64) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Add("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/140.0.0.0 YaBrowser/25.10.0.0 Safari/537.36")
+
+	if end >= start {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, downloaders.Temporary(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return &stream{Reader: io.LimitReader(resp.Body, end-start+1), Closer: resp.Body}, nil
+	case http.StatusOK:
+		if end < start {
+			return resp.Body, nil
+		}
+
+		// CDN проигнорировал Range - пропускаем первые start байт
+		if _, err := io.CopyN(io.Discard, resp.Body, start); err != nil {
+			closeBody(resp.Body)
+
+			return nil, downloaders.Temporary(err)
+		}
+
+		return &stream{Reader: io.LimitReader(resp.Body, end-start+1), Closer: resp.Body}, nil
+	default:
+		closeBody(resp.Body)
+
+		return nil, fmt.Errorf("поток: %w", downloaders.StatusError(resp.StatusCode))
+	}
+}
+
+func closeBody(body io.Closer) {
+	if err := body.Close(); err != nil {
+		utils.Log.Error(err)
+	}
+}
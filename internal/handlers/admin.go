@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	telegramUtils "github.com/StounhandJ/shorts_forward/internal/utils/telegram"
+	"github.com/mymmrac/telego"
+	th "github.com/mymmrac/telego/telegohandler"
+)
+
+// BreakersCommand состояние предохранителей провайдеров, только для администраторов
+func (h handler) BreakersCommand(ctx *th.Context, update telego.Update) error {
+	if !slices.Contains(h.admins, telegramUtils.GetUserID(update)) {
+		return nil
+	}
+
+	var text strings.Builder
+
+	for _, status := range h.breakers.Status() {
+		icon := "🟢"
+		if status.State != "closed" {
+			icon = "🔴"
+		}
+
+		fmt.Fprintf(&text, "%s <b>%s</b>: %s", icon, status.Name, status.State)
+
+		if !status.OpenedAt.IsZero() {
+			fmt.Fprintf(&text, " с %s", status.OpenedAt.Format("15:04:05"))
+		}
+
+		if status.Failures > 0 {
+			fmt.Fprintf(&text, ", неудач подряд %d: %s", status.Failures, html.EscapeString(status.LastError))
+		}
+
+		text.WriteString("\n")
+	}
+
+	if text.Len() == 0 {
+		text.WriteString("Предохранители не настроены")
+	}
+
+	telegramUtils.SendMessage(ctx, true, true, update, text.String())
+
+	return nil
+}
@@ -1,15 +1,16 @@
 package handlers
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"strings"
 
+	"github.com/StounhandJ/shorts_forward/internal/cache"
 	downloadersService "github.com/StounhandJ/shorts_forward/internal/downloaders"
 	"github.com/StounhandJ/shorts_forward/internal/utils"
 	telegramUtils "github.com/StounhandJ/shorts_forward/internal/utils/telegram"
 	"github.com/mymmrac/telego"
 	th "github.com/mymmrac/telego/telegohandler"
-	tu "github.com/mymmrac/telego/telegoutil"
 )
 
 var GlobalCounter = 0
@@ -32,18 +33,19 @@ func (h handler) InlineVideo(ctx *th.Context, query telego.InlineQuery) error {
 		})
 	}
 
-	var downloader downloadersService.IDownloader
+	// ТГ отбрасывает inline ответ примерно через 10 секунд, поэтому время на раскрытие ссылки
+	// и загрузку данных ограничено отдельно от контекста ответа
+	downloadCtx := context.Context(ctx)
+	if h.inlineTimeout > 0 {
+		var cancel context.CancelFunc
 
-	for _, d := range h.downloaders {
-		if d.Valid(url) {
-			downloader = d
-
-			break
-		}
+		downloadCtx, cancel = context.WithTimeout(ctx, h.inlineTimeout)
+		defer cancel()
 	}
 
 	// Загрузчик не найден
-	if downloader == nil {
+	downloader, link, err := h.downloaders.Resolve(downloadCtx, url)
+	if err != nil {
 		return ctx.Bot().AnswerInlineQuery(ctx, &telego.AnswerInlineQueryParams{
 			InlineQueryID: query.ID,
 			Results:       []telego.InlineQueryResult{},
@@ -51,15 +53,60 @@ func (h handler) InlineVideo(ctx *th.Context, query telego.InlineQuery) error {
 		})
 	}
 
+	// Публикация уже загружалась в ТГ - отвечаем по file_id без обращения к платформе.
+	// ТГ отклоняет устаревший file_id, тогда публикация загружается заново
+	if sent, ok := h.cachedSent(link); ok {
+		err := ctx.Bot().AnswerInlineQuery(ctx, &telego.AnswerInlineQueryParams{
+			InlineQueryID: query.ID,
+			Results:       cachedInlineResults(link, sent),
+			CacheTime:     300,
+		})
+		if err == nil {
+			return nil
+		}
+
+		utils.Metrics.Add("file_id_stale", 1)
+		utils.Log.Warnf("%s: ответ по file_id не удался: %s", link.URL, err)
+	}
+
 	// Получение данных о видео
-	metadataVideo, err := downloader.Download(url)
+	metadataVideo, err := downloader.Download(downloadCtx, link.URL)
 	if err != nil {
-		utils.Log.Error(err)
-		results := []telego.InlineQueryResult{}
+		utils.Log.Errorf("%s: %s", link.URL, err)
+		title, text := errorText(err, telegramUtils.DeliveryURL)
 
 		return ctx.Bot().AnswerInlineQuery(ctx, &telego.AnswerInlineQueryParams{
 			InlineQueryID: query.ID,
-			Results:       results,
+			Results:       inlineErrorResult(link, title, text),
+			CacheTime:     0,
+		})
+	}
+
+	// Загрузка в чат-хранилище, чтобы ТГ не пришлось скачивать файл по ссылке
+	if h.storage.enabled(link.Platform) {
+		sent, err := h.uploadToStorage(ctx.WithContext(downloadCtx), link, metadataVideo)
+		if err == nil {
+			utils.Metrics.Add("storage_upload_ok", 1)
+
+			return ctx.Bot().AnswerInlineQuery(ctx, &telego.AnswerInlineQueryParams{
+				InlineQueryID: query.ID,
+				Results:       cachedInlineResults(link, sent),
+				CacheTime:     300,
+			})
+		}
+
+		utils.Metrics.Add("storage_upload_failed", 1)
+		utils.Log.Warnf("%s: загрузка в чат-хранилище не удалась, отвечаем ссылками: %s", link.URL, err)
+	}
+
+	selected, err := h.selectItems(downloadCtx, metadataVideo, telegramUtils.DeliveryURL, 0)
+	if err != nil {
+		utils.Log.Errorf("%s: %s", link.URL, err)
+		title, text := errorText(err, telegramUtils.DeliveryURL)
+
+		return ctx.Bot().AnswerInlineQuery(ctx, &telego.AnswerInlineQueryParams{
+			InlineQueryID: query.ID,
+			Results:       inlineErrorResult(link, title, text),
 			CacheTime:     0,
 		})
 	}
@@ -69,29 +116,14 @@ func (h handler) InlineVideo(ctx *th.Context, query telego.InlineQuery) error {
 		utils.Log.Infof("Количество запрошенных роликов %d", GlobalCounter)
 	}
 
-	mainInfo := metadataVideo.MainInfo()
 	return ctx.Bot().AnswerInlineQuery(ctx, &telego.AnswerInlineQueryParams{
 		InlineQueryID: query.ID,
-		Results: []telego.InlineQueryResult{
-			&telego.InlineQueryResultVideo{
-				Type:                  telego.ResultTypeVideo,
-				ID:                    metadataVideo.VideoURL[:min(64, len(metadataVideo.VideoURL))],
-				Title:                 metadataVideo.Title[:min(200, len(metadataVideo.Title))],
-				Caption:               fmt.Sprintf("%s\n%s", metadataVideo.Title[:min(900, len(metadataVideo.Title))], mainInfo),
-				VideoURL:              metadataVideo.VideoURL,
-				ThumbnailURL:          metadataVideo.ThumbnailURL,
-				MimeType:              metadataVideo.MimeType,
-				ShowCaptionAboveMedia: true,
-				Description:           fmt.Sprintf("%s %s", utils.FormatSecondsToMMSS(metadataVideo.Duration), mainInfo),
-				ReplyMarkup:           tu.InlineKeyboard(tu.InlineKeyboardRow(tu.InlineKeyboardButton("Оригинал").WithURL(url))),
-			},
-		},
-		CacheTime: 300,
+		Results:       inlineResults(link, metadataVideo, selected),
+		CacheTime:     300,
 	})
 }
 
 func (h handler) MessageVideo(ctx *th.Context, update telego.Update) error {
-	sorryText := "Сори, с этим видео что-то не так и ТГ не смог его скачать🥲\nПростите и не бейте🙏🏿"
 	url := telegramUtils.GetMessageText(update)
 	// Проверка валидности url
 	if !isAllowedShortURL(url) {
@@ -100,31 +132,54 @@ func (h handler) MessageVideo(ctx *th.Context, update telego.Update) error {
 		return nil
 	}
 
-	var downloader downloadersService.IDownloader
+	// Загрузчик не найден
+	downloader, link, err := h.downloaders.Resolve(ctx, url)
+	if errors.Is(err, downloadersService.ErrProfileURL) {
+		telegramUtils.SendMessage(ctx, false, true, update, "Это ссылка на профиль, пришли ссылку на конкретный ролик или пост")
 
-	for _, d := range h.downloaders {
-		if d.Valid(url) {
-			downloader = d
+		return nil
+	} else if errors.Is(err, downloadersService.ErrStoryURL) {
+		telegramUtils.SendMessage(ctx, false, true, update, "Пришли ссылку на конкретную историю или актуальное, а не на все истории пользователя")
 
-			break
-		}
-	}
+		return nil
+	} else if errors.Is(err, downloadersService.ErrNotMediaURL) {
+		telegramUtils.SendMessage(ctx, false, true, update, "Поддерживается только ссылка на ролик (TikTok, Instagram, YouTube)")
 
-	// Загрузчик не найден
-	if downloader == nil {
+		return nil
+	} else if errors.Is(err, downloadersService.ErrUnsupportedURL) {
 		telegramUtils.SendMessage(ctx, false, true, update, "Поддерживается только TikTok, Instagram, YouTube")
 
 		return nil
+	} else if err != nil {
+		// Платформа поддерживается, но ссылку не удалось раскрыть или разобрать
+		utils.Log.Warnf("%s: %s", url, err)
+
+		_, text := errorText(err, telegramUtils.DeliveryUpload)
+		telegramUtils.SendMessage(ctx, false, true, update, text)
+
+		return nil
+	}
+
+	// Публикация уже загружалась в ТГ - отправляем по file_id без обращения к платформе
+	if sent, ok := h.cachedSent(link); ok {
+		err := sendCached(ctx, update, link, sent)
+		if err == nil {
+			return nil
+		}
+
+		utils.Log.Warnf("%s: отправка по file_id не удалась: %s", link.URL, err)
 	}
 
 	loadMessage := telegramUtils.SendMessage(ctx, false, true, update, "Загрузка....")
 
 	// Получение данных о видео
-	metadataVideo, err := downloader.Download(url)
+	metadataVideo, err := downloader.Download(ctx, link.URL)
 	if err != nil {
-		utils.Log.Error(err)
+		utils.Log.Errorf("%s: %s", link.URL, err)
 		telegramUtils.DeleteMessage(ctx, update, loadMessage)
-		telegramUtils.SendMessage(ctx, false, true, update, sorryText)
+
+		_, text := errorText(err, telegramUtils.DeliveryUpload)
+		telegramUtils.SendMessage(ctx, false, true, update, text)
 
 		return nil
 	}
@@ -134,22 +189,57 @@ func (h handler) MessageVideo(ctx *th.Context, update telego.Update) error {
 		utils.Log.Infof("Количество запрошенных роликов %d", GlobalCounter)
 	}
 
-	err = telegramUtils.EditMessage(ctx, update, loadMessage,
-		fmt.Sprintf("%s\n%s", metadataVideo.Title[:min(900, len(metadataVideo.Title))], metadataVideo.MainInfo()),
-		telegramUtils.InputVideo{
-			URL:  metadataVideo.VideoURL,
-			Name: metadataVideo.Title[:min(200, len(metadataVideo.Title))],
-		},
-		tu.InlineKeyboard(tu.InlineKeyboardRow(tu.InlineKeyboardButton("Оригинал").WithURL(url))))
-	if err != nil {
-		telegramUtils.DeleteMessage(ctx, update, loadMessage)
+	// Сначала ТГ пробует скачать файл по ссылке сам, если не смог - загружаем файл через себя
+	var progress delivery
+
+	modes := []telegramUtils.DeliveryMode{telegramUtils.DeliveryURL, telegramUtils.DeliveryUpload}
+
+	for i, mode := range modes {
+		err = h.sendMedia(ctx, update, &loadMessage, link, metadataVideo, mode, &progress)
+		// Элементы больше лимита последнего способа не отправить никак - альбом уходит без них
+		if errors.Is(err, errItemsLeft) && i == len(modes)-1 {
+			err = nil
+		}
 
-		telegramUtils.SendMessage(ctx, false, true, update, sorryText)
+		if err == nil {
+			h.rememberSent(link, metadataVideo, mediaCaption(metadataVideo), &progress)
+			utils.Log.Infof("%s отправлен через %s", link.URL, mode)
+			utils.Metrics.Add("delivery_"+mode.String(), 1)
+
+			return nil
+		}
+
+		utils.Log.Warnf("%s: отправка через %s не удалась: %s", link.URL, mode, err)
+	}
+
+	utils.Metrics.Add("delivery_failed", 1)
+
+	if loadMessage != 0 {
+		telegramUtils.DeleteMessage(ctx, update, loadMessage)
 	}
 
+	_, text := errorText(err, telegramUtils.DeliveryUpload)
+	telegramUtils.SendMessage(ctx, false, true, update, text)
+
 	return nil
 }
 
+// cachedSent сохранённые file_id публикации
+func (h handler) cachedSent(link downloadersService.Link) (cache.SentMedia, bool) {
+	if h.fileIDs == nil {
+		return cache.SentMedia{}, false
+	}
+
+	sent, ok := h.fileIDs.Get(link.URL)
+	if ok {
+		utils.Metrics.Add("file_id_hit", 1)
+	} else {
+		utils.Metrics.Add("file_id_miss", 1)
+	}
+
+	return sent, ok
+}
+
 // isAllowedShortURL максимально быстрая проверка валидности url на нужные домены
 func isAllowedShortURL(s string) bool {
 	// Минимальная длина: http://youtube.com/XXXX
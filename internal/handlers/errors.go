@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"errors"
+
+	downloadersService "github.com/StounhandJ/shorts_forward/internal/downloaders"
+	telegramUtils "github.com/StounhandJ/shorts_forward/internal/utils/telegram"
+)
+
+const sorryText = "Сори, с этим видео что-то не так и ТГ не смог его скачать🥲\nПростите и не бейте🙏🏿"
+
+// errorText объяснение ошибки загрузки для пользователя: заголовок inline результата и текст сообщения
+func errorText(err error, mode telegramUtils.DeliveryMode) (title, text string) {
+	switch {
+	case errors.Is(err, downloadersService.ErrNotFound):
+		return "Публикация не найдена", "Публикация не найдена: её удалили или в ссылке ошибка🤷"
+	case errors.Is(err, downloadersService.ErrPrivate):
+		return "Закрытая публикация", "Публикация закрыта или доступна только после входа в аккаунт🔒"
+	case errors.Is(err, downloadersService.ErrGeoBlocked):
+		return "Недоступно в регионе", "Платформа не показывает эту публикацию в регионе бота🌍"
+	case errors.Is(err, downloadersService.ErrAgeRestricted):
+		return "Ограничение 18+", "У публикации возрастное ограничение, без входа в аккаунт её не скачать🔞"
+	case errors.Is(err, downloadersService.ErrTooLarge):
+		return "Слишком большой ролик", tooLargeText(mode)
+	case errors.Is(err, downloadersService.ErrUnsupportedContent):
+		return "Не поддерживается", "Такой тип публикации пока не поддерживается (эфиры, истории и т.п.)🙅"
+	case errors.Is(err, downloadersService.ErrRateLimited):
+		return "Слишком много запросов", "Платформа временно ограничила запросы, попробуй ещё раз через пару минут⏳"
+	case downloadersService.IsTemporary(err):
+		return "Платформа не ответила", "Платформа не ответила вовремя, попробуй ещё раз через минуту🔄"
+	default:
+		return "Не удалось загрузить", sorryText
+	}
+}
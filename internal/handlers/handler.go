@@ -1,17 +1,43 @@
 package handlers
 
 import (
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/cache"
 	downloadersService "github.com/StounhandJ/shorts_forward/internal/downloaders"
 	th "github.com/mymmrac/telego/telegohandler"
 )
 
 type handler struct {
-	downloaders []downloadersService.IDownloader
+	downloaders   *downloadersService.Registry
+	selector      *downloadersService.Selector
+	fetcher       *downloadersService.Fetcher
+	fileIDs       cache.Store[cache.SentMedia]
+	storage       StorageChat
+	inlineTimeout time.Duration
+	breakers      *downloadersService.Breakers
+	admins        []int64
 }
 
-func NewHandler(downloaders []downloadersService.IDownloader) handler {
+func NewHandler(
+	downloaders *downloadersService.Registry,
+	selector *downloadersService.Selector,
+	fetcher *downloadersService.Fetcher,
+	fileIDs cache.Store[cache.SentMedia],
+	storage StorageChat,
+	inlineTimeout time.Duration,
+	breakers *downloadersService.Breakers,
+	admins []int64,
+) handler {
 	return handler{
-		downloaders: downloaders,
+		downloaders:   downloaders,
+		selector:      selector,
+		fetcher:       fetcher,
+		fileIDs:       fileIDs,
+		storage:       storage,
+		inlineTimeout: inlineTimeout,
+		breakers:      breakers,
+		admins:        admins,
 	}
 }
 
@@ -19,6 +45,9 @@ func (h handler) SetupRoutes(bh *th.BotHandler) {
 	// Базовые действия
 	bh.Handle(h.StartCommand, th.CommandEqual("start"))
 
+	// Служебные команды
+	bh.Handle(h.BreakersCommand, th.CommandEqual("breakers"))
+
 	bh.HandleInlineQuery(h.InlineVideo)
 	bh.Handle(h.MessageVideo, th.AnyMessage())
 }
@@ -0,0 +1,497 @@
+package handlers
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/StounhandJ/shorts_forward/internal/cache"
+	downloadersService "github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	telegramUtils "github.com/StounhandJ/shorts_forward/internal/utils/telegram"
+	"github.com/mymmrac/telego"
+	th "github.com/mymmrac/telego/telegohandler"
+	tu "github.com/mymmrac/telego/telegoutil"
+)
+
+// selectedItem элемент публикации с выбранным вариантом файла
+type selectedItem struct {
+	index     int
+	item      downloadersService.Item
+	rendition downloadersService.Rendition
+}
+
+// selectItems выбирает для каждого элемента лучший вариант, который ТГ примет при способе отправки mode.
+// maxSize > 0 ужесточает лимит ТГ, например для чата-хранилища.
+// Элементы, которые не влезают в лимит, пропускаются; если не влез ни один - ErrTooLarge
+func (h handler) selectItems(
+	ctx context.Context,
+	media *downloadersService.Media,
+	mode telegramUtils.DeliveryMode,
+	maxSize int64,
+) ([]selectedItem, error) {
+	selected := make([]selectedItem, 0, len(media.Items))
+	tooLarge := false
+
+	for i, item := range media.Items {
+		// ТГ скачивает файл по ссылке без наших cookie
+		if mode == telegramUtils.DeliveryURL {
+			item.Renditions = slices.DeleteFunc(slices.Clone(item.Renditions), func(r downloadersService.Rendition) bool { return r.Cookies })
+		}
+
+		rendition, err := h.selector.Select(ctx, item, itemLimit(mode, item, maxSize))
+		if err != nil {
+			tooLarge = tooLarge || errors.Is(err, downloadersService.ErrTooLarge)
+
+			continue
+		}
+
+		selected = append(selected, selectedItem{
+			index:     i,
+			item:      item,
+			rendition: rendition,
+		})
+	}
+
+	switch {
+	case len(selected) > 0:
+		return selected, nil
+	case tooLarge:
+		return nil, downloadersService.ErrTooLarge
+	default:
+		return nil, errors.New("нет доступных вариантов файла")
+	}
+}
+
+// itemLimit предел размера файла элемента при способе отправки mode, maxSize > 0 ужесточает его
+func itemLimit(mode telegramUtils.DeliveryMode, item downloadersService.Item, maxSize int64) int64 {
+	limit := telegramUtils.SizeLimit(mode, item.Type == downloadersService.MediaTypePhoto)
+	if maxSize > 0 {
+		limit = min(limit, maxSize)
+	}
+
+	return limit
+}
+
+// tooLargeText сообщение пользователю, когда ни один вариант файла не влезает в лимит ТГ
+func tooLargeText(mode telegramUtils.DeliveryMode) string {
+	return fmt.Sprintf("Ролик весит больше %d МБ, ТГ не даёт отправить его ботом🥲", telegramUtils.SizeLimit(mode, false)>>20)
+}
+
+// inlineResults по отдельному inline результату на каждое фото и видео публикации
+func inlineResults(link downloadersService.Link, media *downloadersService.Media, selected []selectedItem) []telego.InlineQueryResult {
+	mainInfo := media.MainInfo()
+	caption := mediaCaption(media)
+	markup := tu.InlineKeyboard(tu.InlineKeyboardRow(tu.InlineKeyboardButton("Оригинал").WithURL(link.URL)))
+
+	results := make([]telego.InlineQueryResult, 0, len(selected))
+
+	for _, s := range selected {
+		id := link.ID()
+		title := utils.TruncateText(media.Title, 200)
+
+		if media.IsAlbum() {
+			id = link.ItemID(s.index)
+			title = fmt.Sprintf("%d/%d %s", s.index+1, len(media.Items), title)
+		}
+
+		if s.item.Type == downloadersService.MediaTypePhoto {
+			results = append(results, &telego.InlineQueryResultPhoto{
+				Type:                  telego.ResultTypePhoto,
+				ID:                    id,
+				PhotoURL:              s.rendition.URL,
+				ThumbnailURL:          s.item.ThumbnailURL,
+				PhotoWidth:            s.rendition.Width,
+				PhotoHeight:           s.rendition.Height,
+				Title:                 title,
+				Description:           mainInfo,
+				Caption:               caption,
+				ShowCaptionAboveMedia: true,
+				ReplyMarkup:           markup,
+			})
+
+			continue
+		}
+
+		results = append(results, &telego.InlineQueryResultVideo{
+			Type:                  telego.ResultTypeVideo,
+			ID:                    id,
+			Title:                 title,
+			Caption:               caption,
+			VideoURL:              s.rendition.URL,
+			ThumbnailURL:          s.item.ThumbnailURL,
+			MimeType:              s.rendition.MimeType,
+			ShowCaptionAboveMedia: true,
+			Description:           fmt.Sprintf("%s %s", utils.FormatSecondsToMMSS(s.item.Duration), mainInfo),
+			ReplyMarkup:           markup,
+		})
+	}
+
+	return results
+}
+
+// inlineErrorResult inline результат с объяснением, почему ролик не отправить
+func inlineErrorResult(link downloadersService.Link, title, text string) []telego.InlineQueryResult {
+	return []telego.InlineQueryResult{
+		&telego.InlineQueryResultArticle{
+			Type:        telego.ResultTypeArticle,
+			ID:          link.ID(),
+			Title:       title,
+			Description: text,
+			InputMessageContent: &telego.InputTextMessageContent{
+				MessageText: fmt.Sprintf("%s\n%s", text, link.URL),
+			},
+		},
+	}
+}
+
+// delivery уже отправленные элементы публикации. Если альбом дошёл частично, следующий способ отправки
+// досылает только оставшиеся элементы, а не весь альбом заново
+type delivery struct {
+	items    []selectedItem
+	messages []telego.Message
+}
+
+func (d *delivery) sent(index int) bool {
+	return slices.ContainsFunc(d.items, func(s selectedItem) bool { return s.index == index })
+}
+
+// add запоминает элементы, на которые пришли сообщения: ТГ отвечает на них по порядку
+func (d *delivery) add(items []selectedItem, messages []telego.Message) {
+	d.items = append(d.items, items[:len(messages)]...)
+	d.messages = append(d.messages, messages...)
+}
+
+// errItemsLeft часть элементов альбома не влезла в лимит способа отправки и ещё не отправлена
+var errItemsLeft = errors.New("часть элементов альбома больше лимита")
+
+// sendMedia отправляет публикацию способом mode: одиночный файл заменяет сообщение загрузки,
+// альбом уходит отдельной группой. При загрузке через себя файлы скачиваются http клиентом бота
+// по мере отправки, так что открыт только файл, который ТГ принимает сейчас
+func (h handler) sendMedia(
+	ctx *th.Context,
+	update telego.Update,
+	loadMessage *int,
+	link downloadersService.Link,
+	media *downloadersService.Media,
+	mode telegramUtils.DeliveryMode,
+	progress *delivery,
+) error {
+	selected, err := h.selectItems(ctx, media, mode, 0)
+	if err != nil {
+		return err
+	}
+
+	pending := slices.DeleteFunc(selected, func(s selectedItem) bool { return progress.sent(s.index) })
+	files := make([]any, 0, len(pending))
+	bodies := make([]*lazyFile, 0, len(pending))
+
+	defer func() {
+		for _, body := range bodies {
+			if err := body.Close(); err != nil {
+				utils.Log.Error(err)
+			}
+		}
+	}()
+
+	for _, s := range pending {
+		var file io.Reader
+
+		if mode == telegramUtils.DeliveryUpload {
+			limit := itemLimit(mode, s.item, 0)
+			body := &lazyFile{open: func() (io.ReadCloser, error) { return h.fetcher.Open(ctx, s.rendition.URL, limit) }}
+
+			bodies = append(bodies, body)
+			file = body
+		}
+
+		files = append(files, inputFile(media, s, file))
+	}
+
+	caption := mediaCaption(media)
+
+	var messages []telego.Message
+
+	switch {
+	case len(files) == 0:
+	case len(files) == 1 && len(progress.items) == 0:
+		keyboard := tu.InlineKeyboard(tu.InlineKeyboardRow(tu.InlineKeyboardButton("Оригинал").WithURL(link.URL)))
+
+		var msg *telego.Message
+
+		// Сообщение загрузки не отправилось или уже удалено - файл уходит новым сообщением
+		if *loadMessage == 0 {
+			msg, err = telegramUtils.SendFileMessage(ctx, update, htmlCaption(caption, captionLimit), files[0], keyboard)
+		} else {
+			msg, err = telegramUtils.EditMessage(ctx, update, *loadMessage, htmlCaption(caption, captionLimit), files[0], keyboard)
+		}
+
+		if err != nil {
+			return bodyError(bodies, err)
+		}
+
+		if msg != nil {
+			messages = append(messages, *msg)
+		}
+	case len(files) == 1:
+		// Последний элемент альбома, часть которого уже отправлена: группа из одного файла невозможна
+		msg, err := telegramUtils.SendFile(ctx, telegramUtils.GetUserID(update), files[0])
+		if err != nil {
+			return bodyError(bodies, err)
+		}
+
+		messages = append(messages, *msg)
+	default:
+		// В альбоме нельзя прикрепить кнопку, поэтому ссылка на оригинал идёт в подписи
+		if *loadMessage != 0 {
+			telegramUtils.DeleteMessage(ctx, update, *loadMessage)
+			*loadMessage = 0
+		}
+
+		// Подпись уже стоит на первом отправленном элементе
+		text := ""
+		if len(progress.items) == 0 {
+			text = albumCaption(caption, link)
+		}
+
+		messages, err = telegramUtils.SendMediaGroup(ctx, true, update, text, files...)
+		if err != nil {
+			progress.add(pending, messages)
+
+			return bodyError(bodies, err)
+		}
+	}
+
+	progress.add(pending, messages)
+
+	// Элементы, которые не влезли в лимит этого способа, досылает следующий
+	if len(selected) < len(media.Items) {
+		return errItemsLeft
+	}
+
+	return nil
+}
+
+// bodyError ошибка открытия или чтения файла важнее ошибки ТГ, в которую telego её завернул:
+// по ней видно, что файл, например, больше лимита
+func bodyError(bodies []*lazyFile, err error) error {
+	for _, body := range bodies {
+		if body.err != nil {
+			return body.err
+		}
+	}
+
+	return err
+}
+
+// lazyFile тело файла, которое скачивается, когда его начинают читать, и закрывается, когда дочитано
+type lazyFile struct {
+	open func() (io.ReadCloser, error)
+	body io.ReadCloser
+	done bool
+	err  error
+}
+
+func (l *lazyFile) Read(p []byte) (int, error) {
+	if l.done {
+		return 0, io.EOF
+	}
+
+	if l.body == nil {
+		body, err := l.open()
+		if err != nil {
+			l.err = err
+
+			return 0, err
+		}
+
+		l.body = body
+	}
+
+	n, err := l.body.Read(p)
+
+	switch {
+	case errors.Is(err, io.EOF):
+		l.done = true
+
+		if closeErr := l.Close(); closeErr != nil {
+			return n, closeErr
+		}
+	case err != nil:
+		l.err = err
+	}
+
+	return n, err
+}
+
+func (l *lazyFile) Close() error {
+	if l.body == nil {
+		return nil
+	}
+
+	body := l.body
+	l.body = nil
+
+	return body.Close()
+}
+
+func mediaCaption(media *downloadersService.Media) string {
+	return fmt.Sprintf("%s\n%s", utils.TruncateText(media.Title, 900), media.MainInfo())
+}
+
+// captionLimit лимит ТГ на подпись к файлу, символов
+const captionLimit = 1024
+
+// htmlCaption подпись для отправки с разметкой HTML. Исходный текст обрезается так,
+// чтобы после экранирования влезть в limit: обрезка уже экранированного текста может разорвать &amp;
+func htmlCaption(caption string, limit int) string {
+	runes := []rune(caption)
+
+	for {
+		escaped := html.EscapeString(string(runes))
+
+		over := utf8.RuneCountInString(escaped) - limit
+		if over <= 0 {
+			return escaped
+		}
+
+		runes = runes[:max(0, len(runes)-over)]
+	}
+}
+
+// albumCaption подпись альбома со ссылкой на оригинал: кнопку к альбому не прикрепить
+func albumCaption(caption string, link downloadersService.Link) string {
+	original := fmt.Sprintf("\n<a href=\"%s\">Оригинал</a>", html.EscapeString(link.URL))
+
+	return htmlCaption(caption, captionLimit-utf8.RuneCountInString(original)) + original
+}
+
+// rememberSent сохраняет file_id отправленных файлов, чтобы в следующий раз не скачивать публикацию
+func (h handler) rememberSent(
+	link downloadersService.Link,
+	media *downloadersService.Media,
+	caption string,
+	progress *delivery,
+) {
+	if h.fileIDs == nil || len(progress.items) == 0 {
+		return
+	}
+
+	sent := cache.SentMedia{
+		Title:   utils.TruncateText(media.Title, 200),
+		Caption: caption,
+		Files:   make([]cache.SentFile, len(progress.items)),
+	}
+
+	// Элементы, досланные другим способом, встают на свои места в альбоме
+	order := make([]int, len(progress.items))
+	for i := range order {
+		order[i] = i
+	}
+
+	slices.SortFunc(order, func(a, b int) int { return cmp.Compare(progress.items[a].index, progress.items[b].index) })
+
+	for i, k := range order {
+		s := progress.items[k]
+
+		fileID := telegramUtils.FileID(&progress.messages[k])
+		if fileID == "" {
+			return
+		}
+
+		sent.Files[i] = cache.SentFile{
+			FileID:    fileID,
+			Photo:     s.item.Type == downloadersService.MediaTypePhoto,
+			Rendition: s.rendition.String(),
+		}
+	}
+
+	h.fileIDs.Set(link.URL, sent)
+}
+
+// sendCached отправляет публикацию по сохранённым file_id
+func sendCached(ctx *th.Context, update telego.Update, link downloadersService.Link, sent cache.SentMedia) error {
+	files := make([]any, 0, len(sent.Files))
+
+	for _, f := range sent.Files {
+		if f.Photo {
+			files = append(files, telegramUtils.InputPhoto{FileID: f.FileID})
+		} else {
+			files = append(files, telegramUtils.InputVideo{FileID: f.FileID, Name: sent.Title})
+		}
+	}
+
+	if len(files) == 1 {
+		messageID := telegramUtils.SendMessage(ctx, false, true, update, htmlCaption(sent.Caption, captionLimit), files[0],
+			tu.InlineKeyboard(tu.InlineKeyboardRow(tu.InlineKeyboardButton("Оригинал").WithURL(link.URL))))
+		if messageID == 0 {
+			return errors.New("не удалось отправить по file_id")
+		}
+
+		return nil
+	}
+
+	_, err := telegramUtils.SendMediaGroup(ctx, true, update, albumCaption(sent.Caption, link), files...)
+
+	return err
+}
+
+// cachedInlineResults inline результаты по сохранённым file_id
+func cachedInlineResults(link downloadersService.Link, sent cache.SentMedia) []telego.InlineQueryResult {
+	markup := tu.InlineKeyboard(tu.InlineKeyboardRow(tu.InlineKeyboardButton("Оригинал").WithURL(link.URL)))
+	results := make([]telego.InlineQueryResult, 0, len(sent.Files))
+
+	for i, f := range sent.Files {
+		id := link.ID()
+		title := sent.Title
+
+		if len(sent.Files) > 1 {
+			id = link.ItemID(i)
+			title = fmt.Sprintf("%d/%d %s", i+1, len(sent.Files), title)
+		}
+
+		if f.Photo {
+			results = append(results, &telego.InlineQueryResultCachedPhoto{
+				Type:                  telego.ResultTypePhoto,
+				ID:                    id,
+				PhotoFileID:           f.FileID,
+				Title:                 title,
+				Caption:               sent.Caption,
+				ShowCaptionAboveMedia: true,
+				ReplyMarkup:           markup,
+			})
+
+			continue
+		}
+
+		results = append(results, &telego.InlineQueryResultCachedVideo{
+			Type:                  telego.ResultTypeVideo,
+			ID:                    id,
+			VideoFileID:           f.FileID,
+			Title:                 title,
+			Caption:               sent.Caption,
+			ShowCaptionAboveMedia: true,
+			ReplyMarkup:           markup,
+		})
+	}
+
+	return results
+}
+
+// inputFile файл элемента для отправки сообщением (InputVideo, InputPhoto)
+func inputFile(media *downloadersService.Media, s selectedItem, file io.Reader) any {
+	if s.item.Type == downloadersService.MediaTypePhoto {
+		return telegramUtils.InputPhoto{URL: s.rendition.URL, File: file}
+	}
+
+	return telegramUtils.InputVideo{
+		URL:  s.rendition.URL,
+		Name: utils.TruncateText(media.Title, 200),
+		File: file,
+	}
+}
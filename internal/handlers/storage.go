@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/cache"
+	downloadersService "github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	telegramUtils "github.com/StounhandJ/shorts_forward/internal/utils/telegram"
+	th "github.com/mymmrac/telego/telegohandler"
+)
+
+// StorageChat приватный чат или канал, куда бот загружает файлы, чтобы отвечать на inline запросы по file_id.
+// Так ТГ не нужно самому скачивать файл по ссылке
+type StorageChat struct {
+	ChatID int64
+	// Budget сколько времени inline запроса можно потратить на загрузку, дальше - ответ ссылками
+	Budget time.Duration
+	// MaxSize файлы больше этого размера (байт) отдаются ссылками
+	MaxSize int64
+	// Platforms для каких платформ используется, пусто - для всех
+	Platforms []string
+}
+
+func (s StorageChat) enabled(platform string) bool {
+	return s.ChatID != 0 && (len(s.Platforms) == 0 || slices.Contains(s.Platforms, platform))
+}
+
+// uploadToStorage загружает файлы публикации в чат-хранилище и запоминает их file_id
+func (h handler) uploadToStorage(
+	ctx *th.Context,
+	link downloadersService.Link,
+	media *downloadersService.Media,
+) (cache.SentMedia, error) {
+	if h.storage.Budget > 0 {
+		var cancel func()
+
+		ctx, cancel = ctx.WithTimeout(h.storage.Budget)
+		defer cancel()
+	}
+
+	// Вариант выбирается сразу под лимит хранилища: если лучший в него не влезает, загружается вариант поменьше
+	selected, err := h.selectItems(ctx, media, telegramUtils.DeliveryUpload, h.storage.MaxSize)
+	if err != nil {
+		return cache.SentMedia{}, err
+	}
+
+	// Неполный альбом не кэшируется: по file_id его потом отправляли бы без пропущенных элементов
+	if len(selected) != len(media.Items) {
+		return cache.SentMedia{}, downloadersService.ErrTooLarge
+	}
+
+	sent := cache.SentMedia{
+		Title:   utils.TruncateText(media.Title, 200),
+		Caption: mediaCaption(media),
+		Files:   make([]cache.SentFile, 0, len(selected)),
+	}
+
+	for _, s := range selected {
+		fileID, err := h.uploadFile(ctx, media, s, itemLimit(telegramUtils.DeliveryUpload, s.item, h.storage.MaxSize))
+		if err != nil {
+			return cache.SentMedia{}, err
+		}
+
+		sent.Files = append(sent.Files, cache.SentFile{
+			FileID:    fileID,
+			Photo:     s.item.Type == downloadersService.MediaTypePhoto,
+			Rendition: s.rendition.String(),
+		})
+	}
+
+	if h.fileIDs != nil {
+		h.fileIDs.Set(link.URL, sent)
+	}
+
+	return sent, nil
+}
+
+func (h handler) uploadFile(ctx *th.Context, media *downloadersService.Media, s selectedItem, limit int64) (string, error) {
+	rc, err := h.fetcher.Open(ctx, s.rendition.URL, limit)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		if err := rc.Close(); err != nil {
+			utils.Log.Error(err)
+		}
+	}()
+
+	msg, err := telegramUtils.SendFile(ctx, h.storage.ChatID, inputFile(media, s, rc))
+	if err != nil {
+		return "", err
+	}
+
+	fileID := telegramUtils.FileID(msg)
+	if fileID == "" {
+		return "", errors.New("в ответе ТГ нет file_id")
+	}
+
+	return fileID, nil
+}
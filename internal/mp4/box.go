@@ -0,0 +1,213 @@
+package mp4
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrInvalid файл не удалось разобрать как MP4
+	ErrInvalid = errors.New("mp4: некорректный файл")
+	// ErrUnsupported файл корректный, но переложить его без потерь нельзя
+	ErrUnsupported = errors.New("mp4: неподдерживаемый файл")
+)
+
+// box атом MP4: тип и содержимое без заголовка
+type box struct {
+	typ    string
+	offset int64 // смещение начала атома в файле
+	header int
+	data   []byte
+}
+
+// parseBoxes разбирает атомы, лежащие подряд в data. base - смещение data в файле
+func parseBoxes(data []byte, base int64) ([]box, error) {
+	var boxes []box
+
+	for pos := 0; pos < len(data); {
+		if len(data)-pos < 8 {
+			return nil, fmt.Errorf("%w: обрезанный заголовок атома", ErrInvalid)
+		}
+
+		size := uint64(binary.BigEndian.Uint32(data[pos:]))
+		typ := string(data[pos+4 : pos+8])
+		header := 8
+
+		switch size {
+		case 0:
+			// атом до конца файла
+			size = uint64(len(data) - pos)
+		case 1:
+			if len(data)-pos < 16 {
+				return nil, fmt.Errorf("%w: обрезанный заголовок атома %s", ErrInvalid, typ)
+			}
+
+			size = binary.BigEndian.Uint64(data[pos+8:])
+			header = 16
+		}
+
+		if size < uint64(header) || size > uint64(len(data)-pos) {
+			return nil, fmt.Errorf("%w: размер атома %s выходит за границы", ErrInvalid, typ)
+		}
+
+		boxes = append(boxes, box{
+			typ:    typ,
+			offset: base + int64(pos),
+			header: header,
+			data:   data[pos+header : pos+int(size)],
+		})
+		pos += int(size)
+	}
+
+	return boxes, nil
+}
+
+// child первый вложенный атом по пути типов: child("mdia", "minf", "stbl")
+func (b box) child(path ...string) (box, error) {
+	c, ok, err := b.find(path...)
+	if err != nil {
+		return box{}, err
+	}
+
+	if !ok {
+		return box{}, fmt.Errorf("%w: нет атома %s", ErrInvalid, strings.Join(path, "/"))
+	}
+
+	return c, nil
+}
+
+// find как child, но отсутствие атома не ошибка
+func (b box) find(path ...string) (box, bool, error) {
+	current := b
+
+	for _, typ := range path {
+		children, err := parseBoxes(current.data, current.offset+int64(current.header))
+		if err != nil {
+			return box{}, false, err
+		}
+
+		found := false
+
+		for _, c := range children {
+			if c.typ == typ {
+				current = c
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return box{}, false, nil
+		}
+	}
+
+	return current, true, nil
+}
+
+// children вложенные атомы указанного типа
+func (b box) children(typ string) ([]box, error) {
+	all, err := parseBoxes(b.data, b.offset+int64(b.header))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []box
+
+	for _, c := range all {
+		if c.typ == typ {
+			result = append(result, c)
+		}
+	}
+
+	return result, nil
+}
+
+// reader последовательное чтение полей атома с проверкой границ
+type reader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *reader) need(n int) bool {
+	if r.err != nil {
+		return false
+	}
+
+	if len(r.data)-r.pos < n {
+		r.err = fmt.Errorf("%w: обрезанный атом", ErrInvalid)
+
+		return false
+	}
+
+	return true
+}
+
+func (r *reader) skip(n int) {
+	if r.need(n) {
+		r.pos += n
+	}
+}
+
+func (r *reader) u8() uint8 {
+	if !r.need(1) {
+		return 0
+	}
+
+	r.pos++
+
+	return r.data[r.pos-1]
+}
+
+func (r *reader) u16() uint16 {
+	if !r.need(2) {
+		return 0
+	}
+
+	r.pos += 2
+
+	return binary.BigEndian.Uint16(r.data[r.pos-2:])
+}
+
+func (r *reader) u32() uint32 {
+	if !r.need(4) {
+		return 0
+	}
+
+	r.pos += 4
+
+	return binary.BigEndian.Uint32(r.data[r.pos-4:])
+}
+
+func (r *reader) u64() uint64 {
+	if !r.need(8) {
+		return 0
+	}
+
+	r.pos += 8
+
+	return binary.BigEndian.Uint64(r.data[r.pos-8:])
+}
+
+// count число записей таблицы по size байт. Больше, чем помещается в атоме, - ошибка,
+// чтобы битый файл не заставил крутить цикл миллиарды раз
+func (r *reader) count(size int) uint32 {
+	n := r.u32()
+	if r.err == nil && uint64(n)*uint64(size) > uint64(len(r.data)-r.pos) {
+		r.err = fmt.Errorf("%w: таблица выходит за границы атома", ErrInvalid)
+
+		return 0
+	}
+
+	return n
+}
+
+// fullHeader версия и флаги полного атома
+func (r *reader) fullHeader() (uint8, uint32) {
+	v := r.u32()
+
+	return uint8(v >> 24), v & 0xFFFFFF
+}
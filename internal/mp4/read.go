@@ -0,0 +1,650 @@
+package mp4
+
+import (
+	"fmt"
+)
+
+// Track дорожка файла: описание кодека и образцы (кадры видео или фреймы звука)
+type Track struct {
+	// Handler тип дорожки: vide или soun
+	Handler   string
+	Timescale uint32
+	Language  uint16
+	// Width, Height размер кадра в формате 16.16, у звука 0
+	Width, Height uint32
+	// SampleEntry содержимое stsd: кодек и его параметры, копируется как есть
+	SampleEntry []byte
+	Samples     []Sample
+	// Delay задержка начала показа дорожки в единицах Timescale (пустая запись edit list)
+	Delay uint64
+	// Start время дорожки в единицах Timescale, с которого начинается показ (запись edit list).
+	// Edited - у исходной дорожки был edit list, иначе задержку первого кадра Write убирает сам
+	Start  uint64
+	Edited bool
+}
+
+// Sample образец дорожки, Data ссылается на байты исходного файла
+type Sample struct {
+	Data              []byte
+	Duration          uint32
+	CompositionOffset int32
+	Sync              bool
+}
+
+// Duration длительность дорожки в единицах Timescale
+func (t Track) Duration() uint64 {
+	var d uint64
+	for _, s := range t.Samples {
+		d += uint64(s.Duration)
+	}
+
+	return d
+}
+
+const (
+	// sampleIsNonSync флаг образца, который не является ключевым кадром
+	sampleIsNonSync = 0x10000
+	// maxSamples защита от огромных таблиц в битом файле
+	maxSamples = 1 << 24
+)
+
+// ReadTracks разбирает дорожки обычного или фрагментированного (DASH) MP4
+func ReadTracks(file []byte) ([]Track, error) {
+	top, err := parseBoxes(file, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		moov  *box
+		moofs []box
+	)
+
+	for i := range top {
+		switch top[i].typ {
+		case "moov":
+			moov = &top[i]
+		case "moof":
+			moofs = append(moofs, top[i])
+		}
+	}
+
+	if moov == nil {
+		return nil, fmt.Errorf("%w: нет атома moov", ErrInvalid)
+	}
+
+	traks, err := moov.children("trak")
+	if err != nil {
+		return nil, err
+	}
+
+	defaults, err := trackDefaults(*moov)
+	if err != nil {
+		return nil, err
+	}
+
+	timescale, err := movieTimescaleOf(*moov)
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := make([]Track, 0, len(traks))
+
+	for _, trak := range traks {
+		id, track, err := readTrak(trak)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := readEdits(trak, timescale, &track); err != nil {
+			return nil, fmt.Errorf("дорожка %d: %w", id, err)
+		}
+
+		if len(moofs) > 0 {
+			track.Samples, err = fragmentSamples(file, moofs, id, defaults[id])
+		} else {
+			track.Samples, err = tableSamples(file, trak)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("дорожка %d: %w", id, err)
+		}
+
+		tracks = append(tracks, track)
+	}
+
+	return tracks, nil
+}
+
+// readTrak описание дорожки и её ID
+func readTrak(trak box) (uint32, Track, error) {
+	var track Track
+
+	tkhd, err := trak.child("tkhd")
+	if err != nil {
+		return 0, track, err
+	}
+
+	r := &reader{data: tkhd.data}
+
+	version, _ := r.fullHeader()
+	if version == 1 {
+		r.skip(16)
+	} else {
+		r.skip(8)
+	}
+
+	id := r.u32()
+
+	if len(tkhd.data) >= 8 {
+		end := &reader{data: tkhd.data[len(tkhd.data)-8:]}
+		track.Width, track.Height = end.u32(), end.u32()
+	}
+
+	mdhd, err := trak.child("mdia", "mdhd")
+	if err != nil {
+		return 0, track, err
+	}
+
+	r = &reader{data: mdhd.data}
+
+	version, _ = r.fullHeader()
+	if version == 1 {
+		r.skip(16)
+		track.Timescale = r.u32()
+		r.skip(8)
+	} else {
+		r.skip(8)
+		track.Timescale = r.u32()
+		r.skip(4)
+	}
+
+	track.Language = r.u16()
+
+	hdlr, err := trak.child("mdia", "hdlr")
+	if err != nil {
+		return 0, track, err
+	}
+
+	r = &reader{data: hdlr.data}
+	r.skip(8)
+
+	if r.need(4) {
+		track.Handler = string(hdlr.data[8:12])
+	}
+
+	stsd, err := trak.child("mdia", "minf", "stbl", "stsd")
+	if err != nil {
+		return 0, track, err
+	}
+
+	track.SampleEntry = stsd.data
+
+	if r.err != nil || track.Timescale == 0 {
+		return 0, track, fmt.Errorf("%w: заголовок дорожки %d", ErrInvalid, id)
+	}
+
+	return id, track, nil
+}
+
+// movieTimescaleOf Timescale из mvhd, в нём указаны длительности edit list. 0 - mvhd нет
+func movieTimescaleOf(moov box) (uint32, error) {
+	mvhd, ok, err := moov.find("mvhd")
+	if err != nil || !ok {
+		return 0, err
+	}
+
+	r := &reader{data: mvhd.data}
+
+	version, _ := r.fullHeader()
+	if version == 1 {
+		r.skip(16)
+	} else {
+		r.skip(8)
+	}
+
+	return r.u32(), r.err
+}
+
+// readEdits edit list дорожки. Поддерживается то, что пишут кодировщики: необязательная пустая
+// запись (задержка) и одна обычная запись со скоростью 1. Остальное - ErrUnsupported:
+// если его отбросить, звук разойдётся с видео
+func readEdits(trak box, movieTimescale uint32, track *Track) error {
+	elst, ok, err := trak.find("edts", "elst")
+	if err != nil || !ok {
+		return err
+	}
+
+	r := &reader{data: elst.data}
+
+	version, _ := r.fullHeader()
+
+	size := 12
+	if version == 1 {
+		size = 20
+	}
+
+	n := r.count(size)
+
+	for i := uint32(0); i < n && r.err == nil; i++ {
+		var (
+			duration  uint64
+			mediaTime int64
+		)
+
+		if version == 1 {
+			duration, mediaTime = r.u64(), int64(r.u64())
+		} else {
+			duration, mediaTime = uint64(r.u32()), int64(int32(r.u32()))
+		}
+
+		rate := r.u32() // media_rate_integer и media_rate_fraction
+
+		switch {
+		case r.err != nil:
+		case mediaTime == -1 && !track.Edited && movieTimescale > 0:
+			track.Delay += duration * uint64(track.Timescale) / uint64(movieTimescale)
+		case mediaTime >= 0 && rate == 0x10000 && !track.Edited:
+			track.Start, track.Edited = uint64(mediaTime), true
+		default:
+			return fmt.Errorf("%w: edit list из %d записей", ErrUnsupported, n)
+		}
+	}
+
+	return r.err
+}
+
+// fragmentDefaults значения образцов по умолчанию из trex
+type fragmentDefaults struct {
+	duration, size, flags uint32
+}
+
+func trackDefaults(moov box) (map[uint32]fragmentDefaults, error) {
+	defaults := make(map[uint32]fragmentDefaults)
+
+	mvex, err := moov.children("mvex")
+	if err != nil || len(mvex) == 0 {
+		return defaults, err
+	}
+
+	trexs, err := mvex[0].children("trex")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, trex := range trexs {
+		r := &reader{data: trex.data}
+		r.fullHeader()
+
+		id := r.u32()
+		r.skip(4) // default_sample_description_index
+
+		defaults[id] = fragmentDefaults{duration: r.u32(), size: r.u32(), flags: r.u32()}
+		if r.err != nil {
+			return nil, r.err
+		}
+	}
+
+	return defaults, nil
+}
+
+// fragmentSamples образцы дорожки id из фрагментов moof
+func fragmentSamples(file []byte, moofs []box, id uint32, trex fragmentDefaults) ([]Sample, error) {
+	var samples []Sample
+
+	for _, moof := range moofs {
+		trafs, err := moof.children("traf")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, traf := range trafs {
+			tfhd, err := traf.child("tfhd")
+			if err != nil {
+				return nil, err
+			}
+
+			r := &reader{data: tfhd.data}
+			_, flags := r.fullHeader()
+
+			if r.u32() != id {
+				continue
+			}
+
+			// Смещения данных считаются от начала moof, если не указано иное
+			base := moof.offset
+			defaults := trex
+
+			if flags&0x1 != 0 {
+				base = int64(r.u64())
+			}
+
+			if flags&0x2 != 0 {
+				r.skip(4)
+			}
+
+			if flags&0x8 != 0 {
+				defaults.duration = r.u32()
+			}
+
+			if flags&0x10 != 0 {
+				defaults.size = r.u32()
+			}
+
+			if flags&0x20 != 0 {
+				defaults.flags = r.u32()
+			}
+
+			if r.err != nil {
+				return nil, r.err
+			}
+
+			truns, err := traf.children("trun")
+			if err != nil {
+				return nil, err
+			}
+
+			offset := base
+
+			for _, trun := range truns {
+				samples, offset, err = readTrun(file, trun, samples, base, offset, defaults)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return samples, nil
+}
+
+// readTrun добавляет образцы trun. Следующий trun без data_offset продолжает данные предыдущего
+func readTrun(file []byte, trun box, samples []Sample, base, offset int64, defaults fragmentDefaults) ([]Sample, int64, error) {
+	r := &reader{data: trun.data}
+	version, flags := r.fullHeader()
+	count := r.u32()
+
+	if count > maxSamples || len(samples)+int(count) > maxSamples {
+		return nil, 0, fmt.Errorf("%w: trun", ErrInvalid)
+	}
+
+	if flags&0x1 != 0 {
+		offset = base + int64(int32(r.u32()))
+	}
+
+	firstFlags, hasFirstFlags := defaults.flags, false
+	if flags&0x4 != 0 {
+		firstFlags, hasFirstFlags = r.u32(), true
+	}
+
+	for i := range count {
+		s := Sample{Duration: defaults.duration}
+		size := defaults.size
+		sampleFlags := defaults.flags
+
+		if flags&0x100 != 0 {
+			s.Duration = r.u32()
+		}
+
+		if flags&0x200 != 0 {
+			size = r.u32()
+		}
+
+		if flags&0x400 != 0 {
+			sampleFlags = r.u32()
+		}
+
+		if i == 0 && hasFirstFlags {
+			sampleFlags = firstFlags
+		}
+
+		if flags&0x800 != 0 {
+			v := r.u32()
+			if version == 0 {
+				s.CompositionOffset = int32(min(v, 1<<31-1))
+			} else {
+				s.CompositionOffset = int32(v)
+			}
+		}
+
+		if r.err != nil {
+			return nil, 0, r.err
+		}
+
+		if offset < 0 || offset+int64(size) > int64(len(file)) {
+			return nil, 0, fmt.Errorf("%w: данные образца за границами файла", ErrInvalid)
+		}
+
+		s.Data = file[offset : offset+int64(size)]
+		s.Sync = sampleFlags&sampleIsNonSync == 0
+		offset += int64(size)
+
+		samples = append(samples, s)
+	}
+
+	return samples, offset, nil
+}
+
+// tableSamples образцы обычного MP4 из таблиц stbl
+func tableSamples(file []byte, trak box) ([]Sample, error) {
+	stbl, err := trak.child("mdia", "minf", "stbl")
+	if err != nil {
+		return nil, err
+	}
+
+	sizes, err := readStsz(stbl)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]Sample, len(sizes))
+
+	if err := readStts(stbl, samples); err != nil {
+		return nil, err
+	}
+
+	if err := readCtts(stbl, samples); err != nil {
+		return nil, err
+	}
+
+	if err := readStss(stbl, samples); err != nil {
+		return nil, err
+	}
+
+	offsets, err := sampleOffsets(stbl, sizes)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range samples {
+		end := offsets[i] + int64(sizes[i])
+		if offsets[i] < 0 || end > int64(len(file)) {
+			return nil, fmt.Errorf("%w: данные образца за границами файла", ErrInvalid)
+		}
+
+		samples[i].Data = file[offsets[i]:end]
+	}
+
+	return samples, nil
+}
+
+func readStsz(stbl box) ([]uint32, error) {
+	stsz, err := stbl.child("stsz")
+	if err != nil {
+		return nil, err
+	}
+
+	r := &reader{data: stsz.data}
+	r.fullHeader()
+
+	size, count := r.u32(), r.u32()
+	if r.err != nil || size == 0 && uint64(count)*4 > uint64(len(stsz.data)) || count > maxSamples {
+		return nil, fmt.Errorf("%w: stsz", ErrInvalid)
+	}
+
+	sizes := make([]uint32, count)
+	for i := range sizes {
+		sizes[i] = size
+		if size == 0 {
+			sizes[i] = r.u32()
+		}
+	}
+
+	return sizes, r.err
+}
+
+func readStts(stbl box, samples []Sample) error {
+	stts, err := stbl.child("stts")
+	if err != nil {
+		return err
+	}
+
+	r := &reader{data: stts.data}
+	r.fullHeader()
+
+	i := 0
+
+	for range r.count(8) {
+		count, delta := r.u32(), r.u32()
+		for ; count > 0 && i < len(samples); count-- {
+			samples[i].Duration = delta
+			i++
+		}
+	}
+
+	return r.err
+}
+
+func readCtts(stbl box, samples []Sample) error {
+	// ctts нет, если порядок показа совпадает с порядком декодирования
+	ctts, ok, err := stbl.find("ctts")
+	if !ok || err != nil {
+		return err
+	}
+
+	r := &reader{data: ctts.data}
+	r.fullHeader()
+
+	i := 0
+
+	for range r.count(8) {
+		count, offset := r.u32(), int32(r.u32())
+		for ; count > 0 && i < len(samples); count-- {
+			samples[i].CompositionOffset = offset
+			i++
+		}
+	}
+
+	return r.err
+}
+
+func readStss(stbl box, samples []Sample) error {
+	stss, ok, err := stbl.find("stss")
+	if err != nil {
+		return err
+	}
+
+	// stss нет, если все образцы ключевые
+	if !ok {
+		for i := range samples {
+			samples[i].Sync = true
+		}
+
+		return nil
+	}
+
+	r := &reader{data: stss.data}
+	r.fullHeader()
+
+	for range r.count(4) {
+		if n := r.u32(); n >= 1 && int(n) <= len(samples) {
+			samples[n-1].Sync = true
+		}
+	}
+
+	return r.err
+}
+
+// sampleOffsets смещения образцов по таблицам stsc и stco/co64
+func sampleOffsets(stbl box, sizes []uint32) ([]int64, error) {
+	var chunks []int64
+
+	if stco, ok, err := stbl.find("stco"); err != nil {
+		return nil, err
+	} else if ok {
+		r := &reader{data: stco.data}
+		r.fullHeader()
+
+		for range r.count(4) {
+			chunks = append(chunks, int64(r.u32()))
+		}
+
+		if r.err != nil {
+			return nil, r.err
+		}
+	} else if co64, ok, err := stbl.find("co64"); err != nil || !ok {
+		return nil, fmt.Errorf("%w: нет stco", ErrInvalid)
+	} else {
+		r := &reader{data: co64.data}
+		r.fullHeader()
+
+		for range r.count(8) {
+			chunks = append(chunks, int64(r.u64()))
+		}
+
+		if r.err != nil {
+			return nil, r.err
+		}
+	}
+
+	stsc, err := stbl.child("stsc")
+	if err != nil {
+		return nil, err
+	}
+
+	type stscEntry struct{ firstChunk, perChunk uint32 }
+
+	var entries []stscEntry
+
+	r := &reader{data: stsc.data}
+	r.fullHeader()
+
+	for range r.count(12) {
+		entries = append(entries, stscEntry{firstChunk: r.u32(), perChunk: r.u32()})
+		r.skip(4)
+	}
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	offsets := make([]int64, 0, len(sizes))
+	sample := 0
+
+	for e, entry := range entries {
+		last := uint32(len(chunks))
+		if e+1 < len(entries) {
+			last = entries[e+1].firstChunk - 1
+		}
+
+		for chunk := entry.firstChunk; chunk <= last && chunk >= 1 && int(chunk) <= len(chunks); chunk++ {
+			offset := chunks[chunk-1]
+
+			for range entry.perChunk {
+				if sample == len(sizes) {
+					return offsets, nil
+				}
+
+				offsets = append(offsets, offset)
+				offset += int64(sizes[sample])
+				sample++
+			}
+		}
+	}
+
+	if len(offsets) != len(sizes) {
+		return nil, fmt.Errorf("%w: stsc описывает %d образцов из %d", ErrInvalid, len(offsets), len(sizes))
+	}
+
+	return offsets, nil
+}
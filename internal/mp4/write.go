@@ -0,0 +1,471 @@
+package mp4
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	movieTimescale = 1000
+	// languageUnd код языка "und" в упакованном виде mdhd
+	languageUnd = 0x55C4
+)
+
+// chunk подряд идущие образцы дорожки, которые лежат в mdat одним куском
+type chunk struct {
+	track  int
+	first  int
+	count  int
+	start  uint64 // время начала в единицах Timescale дорожки
+	size   int64
+	offset int64 // смещение внутри данных mdat
+}
+
+// Write пишет дорожки в обычный (не фрагментированный) MP4 без перекодирования:
+// moov в начале файла, чтобы воспроизведение начиналось до полной загрузки,
+// данные дорожек чередуются кусками примерно по полсекунды
+func Write(w io.Writer, tracks ...Track) error {
+	if len(tracks) == 0 {
+		return fmt.Errorf("%w: нет дорожек", ErrInvalid)
+	}
+
+	for i, t := range tracks {
+		if len(t.Samples) == 0 || t.Timescale == 0 {
+			return fmt.Errorf("%w: пустая дорожка %d", ErrInvalid, i+1)
+		}
+	}
+
+	chunks, mdatSize := interleave(tracks)
+
+	mdatHeader := int64(8)
+	if mdatSize+mdatHeader > math.MaxUint32 {
+		mdatHeader = 16
+	}
+
+	ftyp := ftypBox()
+
+	// Смещения в stco зависят от размера moov, а размер moov от смещений не зависит
+	co64 := false
+	shift := int64(len(ftyp)) + int64(len(moovBox(tracks, chunks, 0, co64))) + mdatHeader
+
+	if shift+mdatSize > math.MaxUint32 {
+		co64 = true
+		shift = int64(len(ftyp)) + int64(len(moovBox(tracks, chunks, 0, co64))) + mdatHeader
+	}
+
+	var header builder
+
+	header.Write(ftyp)
+	header.Write(moovBox(tracks, chunks, shift, co64))
+
+	if mdatHeader == 16 {
+		header.u32(1)
+		header.WriteString("mdat")
+		header.u64(uint64(mdatSize + mdatHeader))
+	} else {
+		header.u32(uint32(mdatSize + mdatHeader))
+		header.WriteString("mdat")
+	}
+
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return err
+	}
+
+	for _, c := range chunks {
+		for _, s := range tracks[c.track].Samples[c.first : c.first+c.count] {
+			if _, err := w.Write(s.Data); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// interleave делит дорожки на куски и упорядочивает их по времени начала
+func interleave(tracks []Track) ([]*chunk, int64) {
+	perTrack := make([][]*chunk, len(tracks))
+
+	for i, t := range tracks {
+		var (
+			current *chunk
+			time    uint64
+		)
+
+		for j, s := range t.Samples {
+			if current == nil || time-current.start >= uint64(t.Timescale/2) {
+				current = &chunk{track: i, first: j, start: time}
+				perTrack[i] = append(perTrack[i], current)
+			}
+
+			current.count++
+			current.size += int64(len(s.Data))
+			time += uint64(s.Duration)
+		}
+	}
+
+	var (
+		ordered []*chunk
+		offset  int64
+	)
+
+	next := make([]int, len(tracks))
+
+	for {
+		best := -1
+
+		for i := range tracks {
+			if next[i] == len(perTrack[i]) {
+				continue
+			}
+
+			if best == -1 || earlier(perTrack[i][next[i]], tracks[i], perTrack[best][next[best]], tracks[best]) {
+				best = i
+			}
+		}
+
+		if best == -1 {
+			return ordered, offset
+		}
+
+		c := perTrack[best][next[best]]
+		c.offset = offset
+		offset += c.size
+		ordered = append(ordered, c)
+		next[best]++
+	}
+}
+
+// earlier кусок a начинается раньше куска b с учётом разных Timescale дорожек
+func earlier(a *chunk, ta Track, b *chunk, tb Track) bool {
+	return a.start*uint64(tb.Timescale) < b.start*uint64(ta.Timescale)
+}
+
+func ftypBox() []byte {
+	var b builder
+
+	b.box("ftyp", func() {
+		b.WriteString("isom")
+		b.u32(0x200)
+
+		for _, brand := range []string{"isom", "iso2", "avc1", "mp41"} {
+			b.WriteString(brand)
+		}
+	})
+
+	return b.Bytes()
+}
+
+func moovBox(tracks []Track, chunks []*chunk, shift int64, co64 bool) []byte {
+	var (
+		b        builder
+		duration uint64
+	)
+
+	for _, t := range tracks {
+		duration = max(duration, movieDuration(t))
+	}
+
+	b.box("moov", func() {
+		b.fullBox("mvhd", 0, 0, func() {
+			b.u32(0) // creation_time
+			b.u32(0) // modification_time
+			b.u32(movieTimescale)
+			b.u32(uint32(duration))
+			b.u32(0x00010000) // rate 1.0
+			b.u16(0x0100)     // volume 1.0
+			b.zeros(10)
+			b.matrix()
+			b.zeros(24)
+			b.u32(uint32(len(tracks) + 1)) // next_track_ID
+		})
+
+		for i, t := range tracks {
+			var own []*chunk
+
+			for _, c := range chunks {
+				if c.track == i {
+					own = append(own, c)
+				}
+			}
+
+			b.trak(uint32(i+1), t, own, shift, co64)
+		}
+	})
+
+	return b.Bytes()
+}
+
+// movieDuration длительность дорожки в единицах movieTimescale
+func movieDuration(t Track) uint64 {
+	return t.Duration() * movieTimescale / uint64(t.Timescale)
+}
+
+func (b *builder) trak(id uint32, t Track, chunks []*chunk, shift int64, co64 bool) {
+	b.box("trak", func() {
+		b.fullBox("tkhd", 0, 0x3, func() { // track_enabled | track_in_movie
+			b.u32(0)
+			b.u32(0)
+			b.u32(id)
+			b.u32(0)
+			b.u32(uint32(movieDuration(t)))
+			b.zeros(8)
+			b.u16(0) // layer
+			b.u16(0) // alternate_group
+
+			if t.Handler == "soun" {
+				b.u16(0x0100)
+			} else {
+				b.u16(0)
+			}
+
+			b.u16(0)
+			b.matrix()
+			b.u32(t.Width)
+			b.u32(t.Height)
+		})
+
+		// Первый кадр с B-кадрами показывается с задержкой, edit list убирает её, чтобы звук не опережал видео.
+		// Edit list исходной дорожки переносится как есть
+		start := t.Start
+		if first := t.Samples[0].CompositionOffset; !t.Edited && first > 0 {
+			start = uint64(first)
+		}
+
+		if start > 0 || t.Delay > 0 {
+			b.box("edts", func() {
+				b.fullBox("elst", 0, 0, func() {
+					if t.Delay > 0 {
+						b.u32(2)
+						b.u32(uint32(t.Delay * movieTimescale / uint64(t.Timescale)))
+						b.u32(0xFFFFFFFF) // media_time -1: пустая запись
+						b.u16(1)
+						b.u16(0)
+					} else {
+						b.u32(1)
+					}
+
+					b.u32(uint32(movieDuration(t)))
+					b.u32(uint32(start))
+					b.u16(1) // media_rate_integer
+					b.u16(0) // media_rate_fraction
+				})
+			})
+		}
+
+		b.box("mdia", func() {
+			b.fullBox("mdhd", 0, 0, func() {
+				b.u32(0)
+				b.u32(0)
+				b.u32(t.Timescale)
+				b.u32(uint32(t.Duration()))
+
+				if t.Language == 0 {
+					b.u16(languageUnd)
+				} else {
+					b.u16(t.Language)
+				}
+
+				b.u16(0)
+			})
+
+			b.fullBox("hdlr", 0, 0, func() {
+				b.u32(0)
+				b.WriteString(t.Handler)
+				b.zeros(12)
+
+				if t.Handler == "soun" {
+					b.WriteString("SoundHandler\x00")
+				} else {
+					b.WriteString("VideoHandler\x00")
+				}
+			})
+
+			b.box("minf", func() {
+				if t.Handler == "soun" {
+					b.fullBox("smhd", 0, 0, func() { b.zeros(4) })
+				} else {
+					b.fullBox("vmhd", 0, 1, func() { b.zeros(8) })
+				}
+
+				b.box("dinf", func() {
+					b.fullBox("dref", 0, 0, func() {
+						b.u32(1)
+						b.fullBox("url ", 0, 1, func() {}) // данные в этом же файле
+					})
+				})
+
+				b.stbl(t, chunks, shift, co64)
+			})
+		})
+	})
+}
+
+func (b *builder) stbl(t Track, chunks []*chunk, shift int64, co64 bool) {
+	b.box("stbl", func() {
+		b.box("stsd", func() { b.Write(t.SampleEntry) })
+
+		b.fullBox("stts", 0, 0, func() {
+			b.timeTable(runs(len(t.Samples), func(i int) uint32 { return t.Samples[i].Duration }))
+		})
+
+		hasCTO, negativeCTO, allSync := false, false, true
+
+		for _, s := range t.Samples {
+			hasCTO = hasCTO || s.CompositionOffset != 0
+			negativeCTO = negativeCTO || s.CompositionOffset < 0
+			allSync = allSync && s.Sync
+		}
+
+		if hasCTO {
+			var version uint8
+			if negativeCTO {
+				version = 1
+			}
+
+			b.fullBox("ctts", version, 0, func() {
+				b.timeTable(runs(len(t.Samples), func(i int) uint32 { return uint32(t.Samples[i].CompositionOffset) }))
+			})
+		}
+
+		if !allSync {
+			b.fullBox("stss", 0, 0, func() {
+				var sync []uint32
+
+				for i, s := range t.Samples {
+					if s.Sync {
+						sync = append(sync, uint32(i+1))
+					}
+				}
+
+				b.u32(uint32(len(sync)))
+
+				for _, n := range sync {
+					b.u32(n)
+				}
+			})
+		}
+
+		b.fullBox("stsc", 0, 0, func() {
+			// Номер первого куска, образцов в куске и sample_description_index
+			entries := runs(len(chunks), func(i int) uint32 { return uint32(chunks[i].count) })
+
+			b.u32(uint32(len(entries)))
+
+			for _, e := range entries {
+				b.u32(e.first)
+				b.u32(e.value)
+				b.u32(1)
+			}
+		})
+
+		b.fullBox("stsz", 0, 0, func() {
+			b.u32(0)
+			b.u32(uint32(len(t.Samples)))
+
+			for _, s := range t.Samples {
+				b.u32(uint32(len(s.Data)))
+			}
+		})
+
+		if co64 {
+			b.fullBox("co64", 0, 0, func() {
+				b.u32(uint32(len(chunks)))
+
+				for _, c := range chunks {
+					b.u64(uint64(shift + c.offset))
+				}
+			})
+		} else {
+			b.fullBox("stco", 0, 0, func() {
+				b.u32(uint32(len(chunks)))
+
+				for _, c := range chunks {
+					b.u32(uint32(shift + c.offset))
+				}
+			})
+		}
+	})
+}
+
+// builder собирает атомы в памяти
+type builder struct {
+	bytes.Buffer
+}
+
+// box атом, размер которого проставляется после записи содержимого
+func (b *builder) box(typ string, body func()) {
+	start := b.Len()
+
+	b.u32(0)
+	b.WriteString(typ)
+	body()
+
+	binary.BigEndian.PutUint32(b.Bytes()[start:], uint32(b.Len()-start))
+}
+
+func (b *builder) fullBox(typ string, version uint8, flags uint32, body func()) {
+	b.box(typ, func() {
+		b.u32(uint32(version)<<24 | flags)
+		body()
+	})
+}
+
+// run подряд идущие образцы (или куски) с одинаковым значением
+type run struct {
+	first, count, value uint32
+}
+
+// runs сжимает значения в таблицу вида stts: номер первого элемента, количество и значение
+func runs(n int, value func(i int) uint32) []run {
+	var entries []run
+
+	for i := range n {
+		v := value(i)
+		if len(entries) > 0 && entries[len(entries)-1].value == v {
+			entries[len(entries)-1].count++
+
+			continue
+		}
+
+		entries = append(entries, run{first: uint32(i + 1), count: 1, value: v})
+	}
+
+	return entries
+}
+
+// timeTable таблица stts или ctts
+func (b *builder) timeTable(entries []run) {
+	b.u32(uint32(len(entries)))
+
+	for _, e := range entries {
+		b.u32(e.count)
+		b.u32(e.value)
+	}
+}
+
+// matrix единичная матрица преобразования
+func (b *builder) matrix() {
+	for _, v := range []uint32{0x00010000, 0, 0, 0, 0x00010000, 0, 0, 0, 0x40000000} {
+		b.u32(v)
+	}
+}
+
+func (b *builder) u16(v uint16) {
+	b.Write(binary.BigEndian.AppendUint16(nil, v))
+}
+
+func (b *builder) u32(v uint32) {
+	b.Write(binary.BigEndian.AppendUint32(nil, v))
+}
+
+func (b *builder) u64(v uint64) {
+	b.Write(binary.BigEndian.AppendUint64(nil, v))
+}
+
+func (b *builder) zeros(n int) {
+	b.Write(make([]byte, n))
+}
@@ -0,0 +1,6 @@
+package utils
+
+import "expvar"
+
+// Metrics счётчики работы бота, отдаются api сервером по /metrics
+var Metrics = expvar.NewMap("shorts_forward")
@@ -3,6 +3,7 @@ package telegram
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"io"
 
 	"github.com/StounhandJ/shorts_forward/internal/utils"
@@ -11,11 +12,27 @@ import (
 	tu "github.com/mymmrac/telego/telegoutil"
 )
 
+// InputVideo видео по file_id, загружаемое ботом (File) или по ссылке - в таком порядке приоритета
 type InputVideo struct {
-	URL  string
-	Name string
+	URL    string
+	Name   string
+	File   io.Reader
+	FileID string
 }
 
+// InputPhoto фото по file_id, загружаемое ботом (File) или по ссылке - в таком порядке приоритета
+type InputPhoto struct {
+	URL    string
+	File   io.Reader
+	FileID string
+}
+
+// Ограничения sendMediaGroup на количество элементов в одной группе
+const (
+	mediaGroupMin = 2
+	mediaGroupMax = 10
+)
+
 func DownloadFile(ctx *th.Context, update telego.Update) (io.Reader, error) {
 	if update.Message == nil || update.Message.Document == nil {
 		return nil, errors.New("файл не прикреплен")
@@ -100,7 +117,7 @@ func GetCurrentMessageID(update telego.Update) int {
 // Отправка сообщения
 func SendMessage(ctx *th.Context, isChat, isSendReplay bool, update telego.Update, text string, args ...any) int {
 	var meesageParam *telego.SendMessageParams
-	var inputFile *InputVideo
+	var file any
 
 	sendChatID := GetUserID(update)
 	if isChat {
@@ -109,7 +126,7 @@ func SendMessage(ctx *th.Context, isChat, isSendReplay bool, update telego.Updat
 
 	meesageParam = &telego.SendMessageParams{
 		ChatID:    tu.ID(sendChatID),
-		Text:      text[:min(4096, len(text))],
+		Text:      utils.TruncateText(text, 4096),
 		ParseMode: "HTML",
 		LinkPreviewOptions: &telego.LinkPreviewOptions{
 			IsDisabled: true,
@@ -129,21 +146,13 @@ func SendMessage(ctx *th.Context, isChat, isSendReplay bool, update telego.Updat
 		switch v.(type) {
 		case telego.ReplyMarkup:
 			meesageParam.ReplyMarkup = v.(telego.ReplyMarkup)
-		case InputVideo:
-			file := v.(InputVideo)
-			inputFile = &file
+		case InputVideo, InputPhoto:
+			file = v
 		}
 	}
 
-	if inputFile != nil {
-		msg, err := ctx.Bot().SendVideo(ctx, &telego.SendVideoParams{
-			ChatID:          meesageParam.ChatID,
-			ReplyParameters: meesageParam.ReplyParameters,
-			ReplyMarkup:     meesageParam.ReplyMarkup,
-			Caption:         meesageParam.Text[:min(1024, len(meesageParam.Text))],
-			ParseMode:       meesageParam.ParseMode,
-			Video:           tu.FileFromURL(inputFile.URL),
-		})
+	if file != nil {
+		msg, err := sendFileMessage(ctx, meesageParam, file)
 		if err != nil {
 			utils.Log.Error(err)
 
@@ -163,6 +172,50 @@ func SendMessage(ctx *th.Context, isChat, isSendReplay bool, update telego.Updat
 	return msg.MessageID
 }
 
+// SendFileMessage отправка файла (InputVideo, InputPhoto) с подписью ответом на текущее сообщение
+func SendFileMessage(ctx *th.Context, update telego.Update, text string, file any, markup telego.ReplyMarkup) (*telego.Message, error) {
+	sendChatID := tu.ID(GetUserID(update))
+
+	return sendFileMessage(ctx, &telego.SendMessageParams{
+		ChatID:      sendChatID,
+		Text:        text,
+		ParseMode:   "HTML",
+		ReplyMarkup: markup,
+		ReplyParameters: &telego.ReplyParameters{
+			MessageID:                GetCurrentMessageID(update),
+			ChatID:                   sendChatID,
+			AllowSendingWithoutReply: true,
+		},
+	}, file)
+}
+
+// sendFileMessage отправка файла с параметрами текстового сообщения, текст становится подписью
+func sendFileMessage(ctx *th.Context, params *telego.SendMessageParams, file any) (*telego.Message, error) {
+	// nolint
+	switch v := file.(type) {
+	case InputVideo:
+		return ctx.Bot().SendVideo(ctx, &telego.SendVideoParams{
+			ChatID:          params.ChatID,
+			ReplyParameters: params.ReplyParameters,
+			ReplyMarkup:     params.ReplyMarkup,
+			Caption:         utils.TruncateText(params.Text, 1024),
+			ParseMode:       params.ParseMode,
+			Video:           inputFileOf(v.URL, v.File, v.FileID, "video.mp4"),
+		})
+	case InputPhoto:
+		return ctx.Bot().SendPhoto(ctx, &telego.SendPhotoParams{
+			ChatID:          params.ChatID,
+			ReplyParameters: params.ReplyParameters,
+			ReplyMarkup:     params.ReplyMarkup,
+			Caption:         utils.TruncateText(params.Text, 1024),
+			ParseMode:       params.ParseMode,
+			Photo:           inputFileOf(v.URL, v.File, v.FileID, "photo.jpg"),
+		})
+	}
+
+	return nil, errors.New("неизвестный тип файла")
+}
+
 // Редактирование текущего сообщения
 func EditCurrentMessage(ctx *th.Context, update telego.Update, text string, args ...any) {
 	if (update.Message != nil && update.Message.Photo != nil) ||
@@ -176,13 +229,13 @@ func EditCurrentMessage(ctx *th.Context, update telego.Update, text string, args
 	EditMessage(ctx, update, GetCurrentMessageID(update), text, args...)
 }
 
-// Редактирование указанного сообщения
-func EditMessage(ctx *th.Context, update telego.Update, messageID int, text string, args ...any) error {
+// Редактирование указанного сообщения. Сообщения нет (не отправилось или удалено) - ошибка
+func EditMessage(ctx *th.Context, update telego.Update, messageID int, text string, args ...any) (*telego.Message, error) {
 	if messageID == 0 {
-		return nil
+		return nil, errors.New("нет сообщения для редактирования")
 	}
 
-	var inputFile *InputVideo
+	var inputMedia telego.InputMedia
 
 	meesageParam := &telego.EditMessageTextParams{
 		ChatID:    tu.ID(GetUserID(update)),
@@ -199,32 +252,150 @@ func EditMessage(ctx *th.Context, update telego.Update, messageID int, text stri
 		switch v.(type) {
 		case *telego.InlineKeyboardMarkup:
 			meesageParam.ReplyMarkup = v.(*telego.InlineKeyboardMarkup)
-		case InputVideo:
-			file := v.(InputVideo)
-			inputFile = &file
+		case InputVideo, InputPhoto:
+			inputMedia = toInputMedia(v, 0, meesageParam.Text, meesageParam.ParseMode)
 		}
 	}
 
-	if inputFile == nil {
-		_, err := ctx.Bot().EditMessageText(ctx, meesageParam)
-		return err
+	if inputMedia == nil {
+		return ctx.Bot().EditMessageText(ctx, meesageParam)
 	}
 
-	_, err := ctx.Bot().EditMessageMedia(ctx, &telego.EditMessageMediaParams{
+	return ctx.Bot().EditMessageMedia(ctx, &telego.EditMessageMediaParams{
 		ChatID:      meesageParam.ChatID,
 		MessageID:   meesageParam.MessageID,
 		ReplyMarkup: meesageParam.ReplyMarkup,
-		Media: &telego.InputMediaVideo{
-			Type:      telego.MediaTypeVideo,
-			Caption:   truncateText(meesageParam.Text, 1024),
-			ParseMode: meesageParam.ParseMode,
-			Media: telego.InputFile{
-				URL: inputFile.URL,
-			},
-		},
+		Media:       inputMedia,
 	})
+}
+
+// Отправка альбома из фото и видео (InputVideo, InputPhoto). Подпись ставится на первый элемент.
+// Больше 10 элементов отправляется несколькими группами
+func SendMediaGroup(ctx *th.Context, isSendReplay bool, update telego.Update, text string, files ...any) ([]telego.Message, error) {
+	sendChatID := tu.ID(GetUserID(update))
+
+	var replyParameters *telego.ReplyParameters
+	if isSendReplay {
+		replyParameters = &telego.ReplyParameters{
+			MessageID:                GetCurrentMessageID(update),
+			ChatID:                   sendChatID,
+			AllowSendingWithoutReply: true,
+		}
+	}
+
+	messages := make([]telego.Message, 0, len(files))
+
+	for i, chunk := range chunkMediaGroup(files) {
+		media := make([]telego.InputMedia, 0, len(chunk))
+		for j, file := range chunk {
+			caption := ""
+			if i == 0 && j == 0 {
+				caption = text
+			}
+
+			media = append(media, toInputMedia(file, len(media), caption, "HTML"))
+		}
+
+		sent, err := ctx.Bot().SendMediaGroup(ctx, &telego.SendMediaGroupParams{
+			ChatID:          sendChatID,
+			ReplyParameters: replyParameters,
+			Media:           media,
+		})
+		if err != nil {
+			return messages, err
+		}
 
-	return err
+		messages = append(messages, sent...)
+	}
+
+	return messages, nil
+}
+
+// SendFile отправка файла (InputVideo, InputPhoto) в указанный чат без уведомления
+func SendFile(ctx *th.Context, chatID int64, file any) (*telego.Message, error) {
+	// nolint
+	switch v := file.(type) {
+	case InputPhoto:
+		return ctx.Bot().SendPhoto(ctx, &telego.SendPhotoParams{
+			ChatID:              tu.ID(chatID),
+			DisableNotification: true,
+			Photo:               inputFileOf(v.URL, v.File, v.FileID, "photo.jpg"),
+		})
+	case InputVideo:
+		return ctx.Bot().SendVideo(ctx, &telego.SendVideoParams{
+			ChatID:              tu.ID(chatID),
+			DisableNotification: true,
+			SupportsStreaming:   true,
+			Video:               inputFileOf(v.URL, v.File, v.FileID, "video.mp4"),
+		})
+	}
+
+	return nil, errors.New("неизвестный тип файла")
+}
+
+// FileID file_id видео или самого большого фото сообщения
+func FileID(msg *telego.Message) string {
+	switch {
+	case msg == nil:
+		return ""
+	case msg.Video != nil:
+		return msg.Video.FileID
+	case len(msg.Photo) > 0:
+		return msg.Photo[len(msg.Photo)-1].FileID
+	default:
+		return ""
+	}
+}
+
+// toInputMedia index нужен для уникальных имён загружаемых файлов внутри одной группы
+func toInputMedia(file any, index int, caption, parseMode string) telego.InputMedia {
+	// nolint
+	switch v := file.(type) {
+	case InputPhoto:
+		return &telego.InputMediaPhoto{
+			Type:      telego.MediaTypePhoto,
+			Caption:   utils.TruncateText(caption, 1024),
+			ParseMode: parseMode,
+			Media:     inputFileOf(v.URL, v.File, v.FileID, fmt.Sprintf("photo%d.jpg", index)),
+		}
+	case InputVideo:
+		return &telego.InputMediaVideo{
+			Type:      telego.MediaTypeVideo,
+			Caption:   utils.TruncateText(caption, 1024),
+			ParseMode: parseMode,
+			Media:     inputFileOf(v.URL, v.File, v.FileID, fmt.Sprintf("video%d.mp4", index)),
+		}
+	}
+
+	return nil
+}
+
+func inputFileOf(url string, file io.Reader, fileID, name string) telego.InputFile {
+	switch {
+	case fileID != "":
+		return tu.FileFromID(fileID)
+	case file != nil:
+		return tu.FileFromReader(file, name)
+	default:
+		return tu.FileFromURL(url)
+	}
+}
+
+// chunkMediaGroup делит альбом на группы по 10 так, чтобы в группе было не меньше 2 элементов
+func chunkMediaGroup(files []any) [][]any {
+	var chunks [][]any
+
+	for len(files) > 0 {
+		n := min(mediaGroupMax, len(files))
+		if rest := len(files) - n; rest > 0 && rest < mediaGroupMin {
+			n -= mediaGroupMin - rest
+		}
+
+		chunks = append(chunks, files[:n])
+		files = files[n:]
+	}
+
+	return chunks
 }
 
 // Удаление текущего сообщения
@@ -253,11 +424,34 @@ func AnswerCallbackQuery(ctx *th.Context, update telego.Update, text string) {
 	}
 }
 
-func truncateText(s string, limit int) string {
-	runes := []rune(s)
-	if len(runes) > limit {
-		return string(runes[:limit])
+// DeliveryMode способ передачи файла в ТГ
+type DeliveryMode int
+
+const (
+	// DeliveryURL ТГ сам скачивает файл по ссылке
+	DeliveryURL DeliveryMode = iota
+	// DeliveryUpload файл загружается ботом через multipart
+	DeliveryUpload
+)
+
+func (m DeliveryMode) String() string {
+	if m == DeliveryUpload {
+		return "upload"
 	}
 
-	return s
+	return "url"
+}
+
+// SizeLimit максимальный размер файла, который ТГ примет при выбранном способе передачи
+func SizeLimit(mode DeliveryMode, photo bool) int64 {
+	switch {
+	case mode == DeliveryURL && photo:
+		return 5 << 20
+	case mode == DeliveryURL:
+		return 20 << 20
+	case photo:
+		return 10 << 20
+	default:
+		return 50 << 20
+	}
 }
@@ -44,3 +44,13 @@ func FormatBigInt(n int) string {
 		return fmt.Sprintf("%.1fB", float64(n)/1_000_000_000.0)
 	}
 }
+
+// TruncateText обрезает строку до limit символов, не разрывая многобайтовые символы
+func TruncateText(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) > limit {
+		return string(runes[:limit])
+	}
+
+	return s
+}
@@ -0,0 +1,153 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/cache"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUEviction(t *testing.T) {
+	lru := cache.NewLRU[int](2, 0)
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+
+	// a становится свежее b, поэтому вытесняется b
+	_, ok := lru.Get("a")
+	require.True(t, ok)
+
+	lru.Set("c", 3)
+
+	_, ok = lru.Get("b")
+	require.False(t, ok)
+
+	v, ok := lru.Get("a")
+	require.True(t, ok)
+	require.Equal(t, 1, v)
+	require.Equal(t, 2, lru.Len())
+
+	lru.Delete("a")
+	lru.Delete("missing")
+
+	_, ok = lru.Get("a")
+	require.False(t, ok)
+	require.Equal(t, 1, lru.Len())
+}
+
+func TestLRUTTL(t *testing.T) {
+	lru := cache.NewLRU[int](10, 40*time.Millisecond)
+	lru.Set("a", 1)
+	lru.Set("b", 2)
+
+	time.Sleep(25 * time.Millisecond)
+
+	// Перезапись продлевает срок
+	lru.Set("b", 3)
+
+	time.Sleep(25 * time.Millisecond)
+
+	_, ok := lru.Get("a")
+	require.False(t, ok)
+
+	v, ok := lru.Get("b")
+	require.True(t, ok)
+	require.Equal(t, 3, v)
+}
+
+func TestDiskSurvivesReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file_ids.jsonl")
+
+	store, err := cache.NewFileIDs(cache.BackendDisk, path, 2)
+	require.NoError(t, err)
+
+	for _, key := range []string{"a", "b", "c"} {
+		store.Set(key, cache.SentMedia{Title: key, Files: []cache.SentFile{{FileID: "id-" + key}}})
+	}
+
+	require.NoError(t, store.(*cache.Disk[cache.SentMedia]).Close())
+
+	// Битая строка после падения не мешает загрузке
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"key":"d","val`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened, err := cache.NewFileIDs(cache.BackendDisk, path, 2)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, reopened.(*cache.Disk[cache.SentMedia]).Close())
+	}()
+
+	_, ok := reopened.Get("a")
+	require.False(t, ok, "a вытеснен по размеру")
+
+	sent, ok := reopened.Get("c")
+	require.True(t, ok)
+	require.Equal(t, "id-c", sent.Files[0].FileID)
+}
+
+func TestDiskCompactsWhileOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file_ids.jsonl")
+
+	store, err := cache.NewDisk[int](path, 4)
+	require.NoError(t, err)
+
+	for i := range 100 {
+		store.Set("key", i)
+	}
+
+	// Файл не растёт от перезаписей одного ключа
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.LessOrEqual(t, strings.Count(string(data), "\n"), 2*4)
+
+	require.NoError(t, store.Close())
+
+	reopened, err := cache.NewDisk[int](path, 4)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, reopened.Close())
+	}()
+
+	v, ok := reopened.Get("key")
+	require.True(t, ok)
+	require.Equal(t, 99, v)
+}
+
+func TestDiskKeepsWritingWhenCompactionFails(t *testing.T) {
+	utils.InitLogger("error")
+
+	path := filepath.Join(t.TempDir(), "file_ids.jsonl")
+
+	store, err := cache.NewDisk[int](path, 4)
+	require.NoError(t, err)
+
+	// Временный файл сжатия не создать: каждое сжатие падает
+	require.NoError(t, os.Mkdir(path+".tmp", 0o700))
+
+	for i := range 100 {
+		store.Set("key", i)
+	}
+
+	require.NoError(t, store.Close())
+	require.NoError(t, os.Remove(path+".tmp"))
+
+	reopened, err := cache.NewDisk[int](path, 4)
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, reopened.Close())
+	}()
+
+	v, ok := reopened.Get("key")
+	require.True(t, ok)
+	require.Equal(t, 99, v)
+}
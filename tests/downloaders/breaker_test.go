@@ -0,0 +1,98 @@
+package downloaders
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+var errLayout = errors.New("html не расшифрован")
+
+func TestBreakerOpensAndProbes(t *testing.T) {
+	utils.InitLogger("error")
+
+	breaker := downloaders.NewBreaker("test", 2, 50*time.Millisecond)
+
+	for range 2 {
+		require.True(t, breaker.Allow())
+		breaker.Done(errLayout)
+	}
+
+	require.Equal(t, downloaders.BreakerOpen, breaker.State())
+	require.False(t, breaker.Allow())
+
+	time.Sleep(60 * time.Millisecond)
+
+	// После cooldown пропускается только один пробный запрос
+	require.True(t, breaker.Allow())
+	require.False(t, breaker.Allow())
+
+	breaker.Done(nil)
+	require.Equal(t, downloaders.BreakerClosed, breaker.State())
+}
+
+func TestBreakerIgnoresContentErrors(t *testing.T) {
+	breaker := downloaders.NewBreaker("test", 1, time.Minute)
+
+	require.True(t, breaker.Allow())
+	breaker.Done(downloaders.ErrNotFound)
+	require.Equal(t, downloaders.BreakerClosed, breaker.State())
+}
+
+func TestFailover(t *testing.T) {
+	utils.InitLogger("error")
+
+	var primaryCalls, fallbackCalls atomic.Int32
+
+	breakers := downloaders.NewBreakers()
+	failover := breakers.Failover("test", []downloaders.Provider{
+		{Name: "primary", Downloader: failingDownloader(&primaryCalls, errLayout, errLayout, errLayout)},
+		{Name: "fallback", Downloader: failingDownloader(&fallbackCalls)},
+	}, 1, time.Minute)
+
+	for range 2 {
+		media, err := failover.Download(context.Background(), "url")
+		require.NoError(t, err)
+		require.NotNil(t, media)
+	}
+
+	// После первой неудачи основной провайдер отключён и не вызывается
+	require.EqualValues(t, 1, primaryCalls.Load())
+	require.EqualValues(t, 2, fallbackCalls.Load())
+
+	statuses := breakers.Status()
+	require.Len(t, statuses, 2)
+	require.Equal(t, "test/primary", statuses[0].Name)
+	require.Equal(t, "open", statuses[0].State)
+	require.Equal(t, "closed", statuses[1].State)
+}
+
+func TestFailoverStopsOnContentError(t *testing.T) {
+	var primaryCalls, fallbackCalls atomic.Int32
+
+	failover := downloaders.NewBreakers().Failover("test", []downloaders.Provider{
+		{Name: "primary", Downloader: failingDownloader(&primaryCalls, downloaders.ErrPrivate)},
+		{Name: "fallback", Downloader: failingDownloader(&fallbackCalls)},
+	}, 1, time.Minute)
+
+	_, err := failover.Download(context.Background(), "url")
+	require.ErrorIs(t, err, downloaders.ErrPrivate)
+	require.EqualValues(t, 0, fallbackCalls.Load())
+}
+
+func TestSelectProviders(t *testing.T) {
+	available := []downloaders.Provider{{Name: "a"}, {Name: "b"}}
+
+	selected, err := downloaders.SelectProviders(available, []string{"b", "a"})
+	require.NoError(t, err)
+	require.Equal(t, "b", selected[0].Name)
+
+	_, err = downloaders.SelectProviders(available, []string{"c"})
+	require.Error(t, err)
+}
@@ -0,0 +1,164 @@
+package downloaders
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/stretchr/testify/require"
+)
+
+type countingDownloader struct {
+	calls   atomic.Int32
+	release chan struct{}
+	err     error
+}
+
+func (d *countingDownloader) Download(context.Context, string) (*downloaders.Media, error) {
+	d.calls.Add(1)
+	<-d.release
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	return &downloaders.Media{Title: "title"}, nil
+}
+
+func TestCacheCollapsesConcurrentRequests(t *testing.T) {
+	upstream := &countingDownloader{release: make(chan struct{})}
+	d := downloaders.Cache("test", 10, time.Minute)(upstream)
+
+	var wg sync.WaitGroup
+
+	for range 5 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			media, err := d.Download(context.Background(), "https://example.com/1")
+			require.NoError(t, err)
+			require.Equal(t, "title", media.Title)
+		}()
+	}
+
+	// Даём всем запросам встать в ожидание первого
+	time.Sleep(20 * time.Millisecond)
+	close(upstream.release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), upstream.calls.Load())
+
+	_, err := d.Download(context.Background(), "https://example.com/1")
+	require.NoError(t, err)
+	require.Equal(t, int32(1), upstream.calls.Load(), "повторный запрос берётся из кэша")
+}
+
+func TestCacheDoesNotStoreErrors(t *testing.T) {
+	upstream := &countingDownloader{release: make(chan struct{}), err: errors.New("upstream")}
+	close(upstream.release)
+
+	d := downloaders.Cache("test", 10, time.Minute)(upstream)
+
+	for range 2 {
+		_, err := d.Download(context.Background(), "https://example.com/1")
+		require.Error(t, err)
+	}
+
+	require.Equal(t, int32(2), upstream.calls.Load())
+}
+
+// ctxDownloader ждёт release или отмены контекста и запоминает, чем закончилось
+type ctxDownloader struct {
+	started  chan struct{}
+	release  chan struct{}
+	canceled chan struct{}
+}
+
+func (d *ctxDownloader) Download(ctx context.Context, _ string) (*downloaders.Media, error) {
+	close(d.started)
+
+	select {
+	case <-d.release:
+		return &downloaders.Media{Title: "title"}, nil
+	case <-ctx.Done():
+		close(d.canceled)
+
+		return nil, ctx.Err()
+	}
+}
+
+func newCtxDownloader() *ctxDownloader {
+	return &ctxDownloader{started: make(chan struct{}), release: make(chan struct{}), canceled: make(chan struct{})}
+}
+
+func TestCacheLeaderCancelDoesNotFailFollowers(t *testing.T) {
+	upstream := newCtxDownloader()
+	d := downloaders.Cache("test", 10, time.Minute)(upstream)
+
+	leaderCtx, cancelLeader := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+
+	go func() {
+		_, err := d.Download(leaderCtx, "https://example.com/1")
+		leaderErr <- err
+	}()
+
+	<-upstream.started
+
+	follower := make(chan *downloaders.Media, 1)
+
+	go func() {
+		media, err := d.Download(context.Background(), "https://example.com/1")
+		require.NoError(t, err)
+		follower <- media
+	}()
+
+	// Даём второму запросу встать в ожидание первого
+	time.Sleep(20 * time.Millisecond)
+	cancelLeader()
+	require.ErrorIs(t, <-leaderErr, context.Canceled)
+
+	close(upstream.release)
+
+	select {
+	case media := <-follower:
+		require.Equal(t, "title", media.Title)
+	case <-time.After(time.Second):
+		t.Fatal("второй запрос не дождался ответа")
+	}
+
+	select {
+	case <-upstream.canceled:
+		t.Fatal("общий запрос отменён вместе с первым")
+	default:
+	}
+}
+
+func TestCacheCancelsWhenNobodyWaits(t *testing.T) {
+	upstream := newCtxDownloader()
+	d := downloaders.Cache("test", 10, time.Minute)(upstream)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		_, err := d.Download(ctx, "https://example.com/1")
+		done <- err
+	}()
+
+	<-upstream.started
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	select {
+	case <-upstream.canceled:
+	case <-time.After(time.Second):
+		t.Fatal("запрос к платформе продолжается без ожидающих")
+	}
+}
@@ -0,0 +1,40 @@
+package downloaders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		code int
+		want error
+	}{
+		{http.StatusNotFound, downloaders.ErrNotFound},
+		{http.StatusGone, downloaders.ErrNotFound},
+		{http.StatusForbidden, downloaders.ErrPrivate},
+		{http.StatusUnavailableForLegalReasons, downloaders.ErrGeoBlocked},
+		{http.StatusTooManyRequests, downloaders.ErrRateLimited},
+		{http.StatusBadGateway, downloaders.ErrTemporary},
+	}
+
+	for _, tt := range tests {
+		require.ErrorIs(t, downloaders.StatusError(tt.code), tt.want, tt.code)
+	}
+
+	require.Error(t, downloaders.StatusError(http.StatusTeapot))
+}
+
+func TestIsTemporary(t *testing.T) {
+	require.True(t, downloaders.IsTemporary(downloaders.Temporary(errors.New("connection reset"))))
+	require.True(t, downloaders.IsTemporary(fmt.Errorf("tikwm: %w", downloaders.ErrRateLimited)))
+	require.True(t, downloaders.IsTemporary(context.DeadlineExceeded))
+	require.False(t, downloaders.IsTemporary(downloaders.ErrNotFound))
+	require.False(t, downloaders.IsTemporary(downloaders.Temporary(context.Canceled)))
+}
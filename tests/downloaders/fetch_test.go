@@ -0,0 +1,51 @@
+package downloaders
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetcherOpen(t *testing.T) {
+	body := strings.Repeat("x", 100)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunked" {
+			// Без Content-Length размер становится известен только при чтении
+			w.Header().Set("Transfer-Encoding", "chunked")
+			_, _ = io.WriteString(w, body)
+			w.(http.Flusher).Flush()
+
+			return
+		}
+
+		_, _ = io.WriteString(w, body)
+	}))
+	defer server.Close()
+
+	fetcher := downloaders.NewFetcher(server.Client())
+
+	rc, err := fetcher.Open(context.Background(), server.URL+"/file", 100)
+	require.NoError(t, err)
+
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.Equal(t, body, string(data))
+	require.NoError(t, rc.Close())
+
+	_, err = fetcher.Open(context.Background(), server.URL+"/file", 50)
+	require.ErrorIs(t, err, downloaders.ErrTooLarge)
+
+	rc, err = fetcher.Open(context.Background(), server.URL+"/chunked", 50)
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(rc)
+	require.ErrorIs(t, err, downloaders.ErrTooLarge)
+	require.NoError(t, rc.Close())
+}
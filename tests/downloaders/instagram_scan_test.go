@@ -0,0 +1,234 @@
+package downloaders
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/downloaders/instagram"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// countingReader считает прочитанные байты страницы
+type countingReader struct {
+	r    io.Reader
+	read *atomic.Int64
+}
+
+func (c countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read.Add(int64(n))
+
+	return n, err
+}
+
+// pageClient клиент, который отдаёт page на странице reelURL, остальные способы получают 404
+func pageClient(page []byte, read *atomic.Int64) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Path != "/reel/DAbCdEfGhIj/" {
+			return &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(countingReader{r: bytes.NewReader(page), read: read}),
+			Request:    req,
+		}, nil
+	})}
+}
+
+// bigPage страница post.html с prefix байт разметки до данных публикации и suffix после
+func bigPage(t testing.TB, prefix, suffix int) []byte {
+	t.Helper()
+
+	post, err := os.ReadFile("testdata/instagram/post.html")
+	require.NoError(t, err)
+
+	filler := "<div class=\"x1n2onr6\"></div>\n"
+
+	var page bytes.Buffer
+	page.WriteString(strings.Repeat(filler, prefix/len(filler)))
+	page.Write(post)
+	page.WriteString(strings.Repeat(filler, suffix/len(filler)))
+
+	return page.Bytes()
+}
+
+func TestInstagramScanStopsAfterData(t *testing.T) {
+	utils.InitLogger("error")
+
+	var read atomic.Int64
+
+	// Ключ данных попадает на границу чтений
+	page := bigPage(t, 32<<10-150, 4<<20)
+
+	media, err := instagram.New(pageClient(page, &read), nil).Download(context.Background(), reelURL)
+	require.NoError(t, err)
+	require.Equal(t, "sunsets", media.Author.Username)
+	require.Equal(t, 12, media.Items[0].Duration)
+	require.Less(t, read.Load(), int64(1<<20))
+}
+
+func TestInstagramScanLimit(t *testing.T) {
+	utils.InitLogger("error")
+
+	var read atomic.Int64
+
+	page := bytes.Repeat([]byte("<div class=\"x1n2onr6\"></div>\n"), (9<<20)/29)
+
+	_, err := instagram.New(pageClient(page, &read), nil).Download(context.Background(), reelURL)
+	require.ErrorContains(t, err, "больше лимита")
+	require.LessOrEqual(t, read.Load(), int64(8<<20+64<<10))
+
+	// Признак удалённой страницы на границе чтений
+	notFound := append(bytes.Repeat([]byte(" "), 32<<10-10), []byte(`<span>Sorry, this page isn't available.</span>`)...)
+
+	_, err = instagram.New(pageClient(notFound, &read), nil).Download(context.Background(), reelURL)
+	require.ErrorIs(t, err, downloaders.ErrNotFound)
+}
+
+// endless страница без конца
+type endless struct{}
+
+func (endless) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = ' '
+	}
+
+	return len(p), nil
+}
+
+// endlessClient отдаёт на любой запрос бесконечную страницу (до 64 МБ) и запоминает, сколько прочитано из каждой
+func endlessClient(mu *sync.Mutex, reads *[]*atomic.Int64) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		read := new(atomic.Int64)
+
+		mu.Lock()
+		*reads = append(*reads, read)
+		mu.Unlock()
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(countingReader{r: io.LimitReader(endless{}, 64<<20), read: read}),
+			Request:    req,
+		}, nil
+	})}
+}
+
+func TestInstagramFallbacksReadLimit(t *testing.T) {
+	utils.InitLogger("error")
+
+	var (
+		mu    sync.Mutex
+		reads []*atomic.Int64
+	)
+
+	// Все способы, включая embed и graphql, читают не больше лимита страницы
+	_, err := instagram.New(endlessClient(&mu, &reads), nil).Download(context.Background(), reelURL)
+	require.Error(t, err)
+	require.Greater(t, len(reads), 1)
+
+	for _, read := range reads {
+		require.LessOrEqual(t, read.Load(), int64(8<<20+64<<10))
+	}
+}
+
+// readAllExtract прежний способ: страница читается целиком, регулярное выражение компилируется на каждый вызов
+func readAllExtract(r io.Reader) (string, bool) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", false
+	}
+
+	html := string(data)
+	re := regexp.MustCompile(`"` + regexp.QuoteMeta("xdt_api__v1__media__shortcode__web_info") + `"\s*:\s*{`)
+
+	loc := re.FindStringIndex(html)
+	if loc == nil {
+		return "", false
+	}
+
+	start := loc[1] - 1
+	depth, inStr, escaped := 0, false, false
+
+	for i := start; i < len(html); i++ {
+		c := html[i]
+
+		switch {
+		case escaped:
+			escaped = false
+		case inStr && c == '\\':
+			escaped = true
+		case c == '"':
+			inStr = !inStr
+		case inStr:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return html[start : i+1], true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// BenchmarkInstagramPage страница ~5 МБ с данными публикации на первых 200 КБ.
+// Потоковое чтение дополнительно разбирает JSON и собирает Media
+func BenchmarkInstagramPage(b *testing.B) {
+	utils.InitLogger("error")
+
+	page := bigPage(b, 200<<10, 5<<20)
+
+	b.Run("readall", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for b.Loop() {
+			if _, ok := readAllExtract(bytes.NewReader(page)); !ok {
+				b.Fatal("данные не найдены")
+			}
+		}
+	})
+
+	b.Run("stream", func(b *testing.B) {
+		var read atomic.Int64
+
+		downloader := instagram.New(pageClient(page, &read), nil)
+
+		b.ReportAllocs()
+
+		for b.Loop() {
+			if _, err := downloader.Download(context.Background(), reelURL); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("stream-parallel", func(b *testing.B) {
+		var read atomic.Int64
+
+		downloader := instagram.New(pageClient(page, &read), nil)
+
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := downloader.Download(context.Background(), reelURL); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+}
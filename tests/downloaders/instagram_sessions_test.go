@@ -0,0 +1,168 @@
+package downloaders
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/downloaders/instagram"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	deadSession = "sessionid=dead; ds_user_id=1; csrftoken=a"
+	liveSession = "sessionid=live; ds_user_id=2; csrftoken=b"
+)
+
+// instagramAPI клиент, который отвечает на запросы api файлами из testdata/instagram.
+// Сессия dead упирается в стену входа, без сессии публикация закрыта стеной входа
+func instagramAPI(t *testing.T, cookies *[]string) *http.Client {
+	t.Helper()
+
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var fixture string
+
+		cookie := req.Header.Get("Cookie")
+		if cookie != "" {
+			*cookies = append(*cookies, cookie)
+		}
+
+		switch {
+		case strings.HasPrefix(req.URL.Path, "/api/v1/") && strings.Contains(cookie, "sessionid=dead"):
+			fixture = "login_required.json"
+		case req.URL.Path == "/api/v1/users/web_profile_info/":
+			require.Equal(t, "someuser", req.URL.Query().Get("username"))
+			fixture = "story_profile.json"
+		case req.URL.Path == "/api/v1/feed/reels_media/" && req.URL.Query().Get("reel_ids") == "111":
+			fixture = "story_reels.json"
+		case req.URL.Path == "/api/v1/feed/reels_media/" && req.URL.Query().Get("reel_ids") == "highlight:17900":
+			fixture = "highlight_reels.json"
+		case req.URL.Path == "/api/v1/media/3466375131966345763/info/":
+			fixture = "media_info.json"
+		case req.URL.Path == "/reel/DAbCdEfGhIj/":
+			fixture = "login.html"
+		case req.URL.Path == "/p/DAbCdEfGhIj/embed/captioned/":
+			fixture = "embed_broken.html"
+		case req.URL.Path == "/graphql/query":
+			fixture = "graphql_login.json"
+		}
+
+		resp := &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: http.NoBody, Request: req}
+
+		if fixture != "" {
+			file, err := os.Open("testdata/instagram/" + fixture)
+			require.NoError(t, err)
+
+			resp.StatusCode = http.StatusOK
+			if fixture == "login_required.json" {
+				resp.StatusCode = http.StatusUnauthorized
+			}
+
+			resp.Body = file
+		}
+
+		return resp, nil
+	})}
+}
+
+func TestInstagramSessionPool(t *testing.T) {
+	pool, err := instagram.NewSessionPool([]string{deadSession, liveSession})
+	require.NoError(t, err)
+	require.Equal(t, 2, pool.Alive())
+
+	_, err = instagram.NewSessionPool([]string{"ds_user_id=1; csrftoken=a"})
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "sessions.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# основной аккаунт\n"+liveSession+"\n\n"), 0o600))
+
+	pool, err = instagram.LoadSessions(path)
+	require.NoError(t, err)
+	require.Equal(t, 1, pool.Alive())
+
+	pool, err = instagram.LoadSessions("")
+	require.NoError(t, err)
+	require.Equal(t, 0, pool.Alive())
+}
+
+func TestInstagramStory(t *testing.T) {
+	utils.InitLogger("error")
+
+	pool, err := instagram.NewSessionPool([]string{deadSession, liveSession})
+	require.NoError(t, err)
+
+	var cookies []string
+
+	media, err := instagram.New(instagramAPI(t, &cookies), pool).
+		Download(context.Background(), "https://www.instagram.com/stories/someuser/3312345678901234567/")
+	require.NoError(t, err)
+
+	require.Equal(t, "История @someuser", media.Title)
+	require.Equal(t, "someuser", media.Author.Username)
+	require.Len(t, media.Items, 1)
+	require.Equal(t, downloaders.MediaTypeVideo, media.Items[0].Type)
+	require.Equal(t, "https://scontent.cdninstagram.com/v/story1.mp4", media.Items[0].Renditions[0].URL)
+
+	// Сессия со стеной входа выведена из ротации, запрос повторён со следующей
+	require.Equal(t, 1, pool.Alive())
+	require.Equal(t, deadSession, cookies[0])
+
+	cookies = nil
+
+	_, err = instagram.New(instagramAPI(t, &cookies), pool).
+		Download(context.Background(), "https://www.instagram.com/stories/someuser/3312345678901234567/")
+	require.NoError(t, err)
+
+	for _, cookie := range cookies {
+		require.Equal(t, liveSession, cookie)
+	}
+
+	// Истекшая история
+	_, err = instagram.New(instagramAPI(t, &cookies), pool).
+		Download(context.Background(), "https://www.instagram.com/stories/someuser/3312345678901234599/")
+	require.ErrorIs(t, err, downloaders.ErrNotFound)
+}
+
+func TestInstagramHighlight(t *testing.T) {
+	utils.InitLogger("error")
+
+	pool, err := instagram.NewSessionPool([]string{liveSession})
+	require.NoError(t, err)
+
+	var cookies []string
+
+	media, err := instagram.New(instagramAPI(t, &cookies), pool).
+		Download(context.Background(), "https://www.instagram.com/stories/highlights/17900/")
+	require.NoError(t, err)
+	require.True(t, media.IsAlbum())
+	require.Equal(t, downloaders.MediaTypePhoto, media.Items[0].Type)
+	require.Equal(t, downloaders.MediaTypeVideo, media.Items[1].Type)
+}
+
+func TestInstagramAPIStrategy(t *testing.T) {
+	utils.InitLogger("error")
+
+	var cookies []string
+
+	// Без сессий истории недоступны
+	_, err := instagram.New(instagramAPI(t, &cookies), nil).
+		Download(context.Background(), "https://www.instagram.com/stories/highlights/17900/")
+	require.ErrorIs(t, err, downloaders.ErrPrivate)
+
+	pool, err := instagram.NewSessionPool([]string{liveSession})
+	require.NoError(t, err)
+
+	before := strategyUsed("api")
+
+	// Публикация открывается только с сессией
+	media, err := instagram.New(instagramAPI(t, &cookies), pool).Download(context.Background(), reelURL)
+	require.NoError(t, err)
+	require.Equal(t, before+1, strategyUsed("api"))
+	require.Equal(t, "Закрытый ролик", media.Title)
+	require.Equal(t, "https://scontent.cdninstagram.com/v/api.mp4", media.Items[0].Renditions[0].URL)
+}
@@ -0,0 +1,203 @@
+package downloaders
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/downloaders/instagram"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+const reelURL = "https://www.instagram.com/reel/DAbCdEfGhIj/"
+
+// instagramFixtures клиент, который отвечает на запросы стратегий файлами из testdata/instagram.
+// Пустое имя файла - 404
+func instagramFixtures(t *testing.T, page, embed, graphql string) *http.Client {
+	t.Helper()
+
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		var fixture string
+
+		switch req.URL.Path {
+		case "/reel/DAbCdEfGhIj/", "/p/C1x-Y_z2AbC/":
+			fixture = page
+		case "/p/DAbCdEfGhIj/embed/captioned/", "/p/C1x-Y_z2AbC/embed/captioned/":
+			fixture = embed
+		case "/graphql/query":
+			require.Equal(t, http.MethodPost, req.Method)
+			fixture = graphql
+		}
+
+		resp := &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: http.NoBody, Request: req}
+
+		if fixture != "" {
+			file, err := os.Open("testdata/instagram/" + fixture)
+			require.NoError(t, err)
+
+			resp.StatusCode = http.StatusOK
+			resp.Body = file
+		}
+
+		return resp, nil
+	})}
+}
+
+// strategyUsed сколько раз публикация была получена стратегией
+func strategyUsed(name string) int64 {
+	if v := utils.Metrics.Get("instagram_strategy_" + name); v != nil {
+		return v.(interface{ Value() int64 }).Value()
+	}
+
+	return 0
+}
+
+func TestInstagramHTMLStrategy(t *testing.T) {
+	utils.InitLogger("error")
+
+	before := strategyUsed("html")
+
+	media, err := instagram.New(instagramFixtures(t, "post.html", "", ""), nil).Download(context.Background(), reelURL)
+	require.NoError(t, err)
+	require.Equal(t, before+1, strategyUsed("html"))
+
+	require.Equal(t, `Закат над морем {тест} "кавычки"`, media.Title)
+	require.Equal(t, "sunsets", media.Author.Username)
+	require.Equal(t, 321, media.LikeCount)
+	require.True(t, media.Music.Original)
+
+	require.Len(t, media.Items, 1)
+	require.Equal(t, 12, media.Items[0].Duration)
+	require.Len(t, media.Items[0].Renditions, 2)
+	require.Equal(t, 720, media.Items[0].Renditions[0].Width)
+}
+
+func TestInstagramEmbedStrategy(t *testing.T) {
+	utils.InitLogger("error")
+
+	before := strategyUsed("embed")
+
+	media, err := instagram.New(instagramFixtures(t, "login.html", "embed.html", ""), nil).
+		Download(context.Background(), "https://www.instagram.com/p/C1x-Y_z2AbC/")
+	require.NoError(t, err)
+	require.Equal(t, before+1, strategyUsed("embed"))
+
+	require.Equal(t, "Отпуск", media.Title)
+	require.Equal(t, 42, media.LikeCount)
+	require.True(t, media.IsAlbum())
+	require.Len(t, media.Items, 2)
+
+	photo := media.Items[0]
+	require.Equal(t, downloaders.MediaTypePhoto, photo.Type)
+	require.Len(t, photo.Renditions, 2)
+
+	require.Equal(t, "https://scontent.cdninstagram.com/v/photo1_1080.jpg", best(t, photo.Renditions...).URL)
+
+	video := media.Items[1]
+	require.Equal(t, downloaders.MediaTypeVideo, video.Type)
+	require.Equal(t, 7, video.Duration)
+	require.Equal(t, "https://scontent.cdninstagram.com/v/clip.mp4", video.Renditions[0].URL)
+}
+
+func TestInstagramGraphQLStrategy(t *testing.T) {
+	utils.InitLogger("error")
+
+	before := strategyUsed("graphql")
+
+	media, err := instagram.New(instagramFixtures(t, "login.html", "embed_broken.html", "graphql.json"), nil).
+		Download(context.Background(), reelURL)
+	require.NoError(t, err)
+	require.Equal(t, before+1, strategyUsed("graphql"))
+
+	require.Equal(t, "Закат над морем", media.Title)
+	require.Equal(t, 5000, media.ViewCount)
+	require.Equal(t, "Original audio", media.Music.Title)
+	require.Len(t, media.Items, 1)
+	require.Equal(t, 12, media.Items[0].Duration)
+	require.True(t, media.Items[0].Renditions[0].HasAudio)
+}
+
+func TestInstagramStrategyErrors(t *testing.T) {
+	utils.InitLogger("error")
+
+	// Все способы упёрлись во вход
+	_, err := instagram.New(instagramFixtures(t, "login.html", "embed_broken.html", "graphql_login.json"), nil).
+		Download(context.Background(), reelURL)
+	require.ErrorIs(t, err, downloaders.ErrPrivate)
+
+	// Удалённая публикация: остальные способы не пробуются
+	_, err = instagram.New(instagramFixtures(t, "not_found.html", "embed.html", "graphql.json"), nil).
+		Download(context.Background(), reelURL)
+	require.ErrorIs(t, err, downloaders.ErrNotFound)
+
+	// Стена входа у одного способа и сбой у другого - это сбой платформы, а не закрытая публикация,
+	// иначе предохранитель не срабатывает
+	fixtures := instagramFixtures(t, "login.html", "", "graphql_login.json")
+	outage := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if strings.HasSuffix(req.URL.Path, "/embed/captioned/") {
+			return &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+		}
+
+		return fixtures.Transport.RoundTrip(req)
+	})}
+
+	_, err = instagram.New(outage, nil).Download(context.Background(), reelURL)
+	require.ErrorIs(t, err, downloaders.ErrTemporary)
+	require.NotErrorIs(t, err, downloaders.ErrPrivate)
+	require.False(t, downloaders.IsContentError(err))
+}
+
+func TestInstagramDashManifest(t *testing.T) {
+	utils.InitLogger("error")
+
+	media, err := instagram.New(instagramFixtures(t, "post_dash.html", "", ""), nil).Download(context.Background(), reelURL)
+	require.NoError(t, err)
+
+	item := media.Items[0]
+	require.Equal(t, 63, item.Duration)
+
+	// Дорожки DASH без звука в варианты файла не попадают
+	require.Len(t, item.Renditions, 2)
+
+	for _, r := range item.Renditions {
+		require.NotContains(t, r.URL, "dash")
+	}
+
+	require.Equal(t, "https://scontent.cdninstagram.com/v/720.mp4", best(t, item.Renditions...).URL)
+}
+
+func TestInstagramDashNotSelected(t *testing.T) {
+	utils.InitLogger("error")
+
+	// Без has_audio progressive варианты считаются немыми, как и дорожка DASH
+	page, err := os.ReadFile("testdata/instagram/post_dash.html")
+	require.NoError(t, err)
+
+	page = bytes.Replace(page, []byte(`"has_audio":true,`), nil, 1)
+
+	var read atomic.Int64
+
+	media, err := instagram.New(pageClient(page, &read), nil).Download(context.Background(), reelURL)
+	require.NoError(t, err)
+
+	item := media.Items[0]
+	require.False(t, item.Renditions[0].HasAudio)
+
+	// Все progressive варианты больше лимита, а дорожка DASH в него помещается
+	sizes := downloaders.NewSelector(&http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK, ContentLength: 100 << 20, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+	})})
+
+	_, err = sizes.Select(context.Background(), item, 50<<20)
+	require.ErrorIs(t, err, downloaders.ErrTooLarge)
+
+	best, err := sizes.Select(context.Background(), item, 200<<20)
+	require.NoError(t, err)
+	require.NotContains(t, best.URL, "dash")
+}
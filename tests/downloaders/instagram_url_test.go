@@ -0,0 +1,96 @@
+package downloaders
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/downloaders/instagram"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstagramURLs(t *testing.T) {
+	const (
+		reelURL = "https://www.instagram.com/reel/DAbCdEfGhIj/"
+		postURL = "https://www.instagram.com/p/C1x-Y_z2AbC/"
+		reelID  = "DAbCdEfGhIj"
+		postID  = "C1x-Y_z2AbC"
+	)
+
+	var requests atomic.Int32
+
+	registry := downloaders.NewRegistry(redirects(&requests, map[string]string{
+		"https://www.instagram.com/share/reel/BAOx5kLm9Q/": reelURL + "?igsh=MWQ1ZGUxMzBkMA==",
+		"https://instagram.com/share/p/BBq7rT2Lx1/":        "https://www.instagram.com/p/" + postID + "/",
+		"https://www.instagram.com/share/BCz1y2x3w4/":      "https://www.instagram.com/accounts/login/?next=%2Freel%2F" + reelID + "%2F%3Figsh%3Dabc",
+	}))
+	registry.Register(instagram.Platform, stubDownloader("instagram"))
+
+	tests := []struct {
+		url     string
+		want    string
+		mediaID string
+	}{
+		{"https://www.instagram.com/reel/DAbCdEfGhIj/", reelURL, reelID},
+		{"https://www.instagram.com/reel/DAbCdEfGhIj/?igsh=MWQ1ZGUxMzBkMA==", reelURL, reelID},
+		{"https://instagram.com/reel/DAbCdEfGhIj", reelURL, reelID},
+		{"https://m.instagram.com/reels/DAbCdEfGhIj/?utm_source=ig_web_copy_link", reelURL, reelID},
+		{"https://instagr.am/reel/DAbCdEfGhIj/", reelURL, reelID},
+		{"https://www.instagram.com/someuser/reel/DAbCdEfGhIj/", reelURL, reelID},
+		{"https://www.instagram.com/p/C1x-Y_z2AbC/?img_index=2", postURL, postID},
+		{"https://instagr.am/p/C1x-Y_z2AbC", postURL, postID},
+		{"https://www.instagram.com/someuser/p/C1x-Y_z2AbC/", postURL, postID},
+		{"https://www.instagram.com/tv/CAbCdEfGhIj/", "https://www.instagram.com/tv/CAbCdEfGhIj/", "CAbCdEfGhIj"},
+		{"https://www.instagram.com/share/reel/BAOx5kLm9Q/", reelURL, reelID},
+		{"https://instagram.com/share/p/BBq7rT2Lx1/", postURL, postID},
+		{"https://www.instagram.com/share/BCz1y2x3w4/", reelURL, reelID},
+		{"https://www.instagram.com/stories/someuser/3312345678901234567/?igsh=abc", "https://www.instagram.com/stories/someuser/3312345678901234567/", "3312345678901234567"},
+		{"https://www.instagram.com/stories/highlights/17900000000000000/", "https://www.instagram.com/stories/highlights/17900000000000000/", "17900000000000000"},
+		{"https://www.instagram.com/s/aGlnaGxpZ2h0OjE3OTAw?story_media_id=1", "https://www.instagram.com/stories/highlights/17900/", "17900"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			_, link, err := registry.Resolve(context.Background(), tt.url)
+			require.NoError(t, err)
+			require.Equal(t, downloaders.Link{Platform: "instagram", URL: tt.want, MediaID: tt.mediaID}, link)
+		})
+	}
+}
+
+func TestInstagramURLErrors(t *testing.T) {
+	var requests atomic.Int32
+
+	registry := downloaders.NewRegistry(redirects(&requests, map[string]string{
+		"https://www.instagram.com/share/reel/BAlogin/": "https://www.instagram.com/accounts/login/",
+	}))
+	registry.Register(instagram.Platform, stubDownloader("instagram"))
+
+	tests := []struct {
+		url string
+		err error
+	}{
+		{"https://www.instagram.com/someuser/", downloaders.ErrProfileURL},
+		{"https://instagram.com/someuser", downloaders.ErrProfileURL},
+		{"https://www.instagram.com/someuser/reels/", downloaders.ErrProfileURL},
+		{"https://www.instagram.com/stories/someuser/", downloaders.ErrStoryURL},
+		{"https://www.instagram.com/stories/someuser/abc/", downloaders.ErrStoryURL},
+		{"https://www.instagram.com/s/bm90LWEtaGlnaGxpZ2h0", downloaders.ErrStoryURL},
+		{"https://www.instagram.com/reels/audio/1234567890/", downloaders.ErrNotMediaURL},
+		{"https://www.instagram.com/explore/tags/cats/", downloaders.ErrNotMediaURL},
+		{"https://www.instagram.com/share/reel/BAlogin/", downloaders.ErrNotMediaURL},
+		{"https://www.instagram.com/", downloaders.ErrNotMediaURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			_, _, err := registry.Resolve(context.Background(), tt.url)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	// Профиль и история всё равно не ролик
+	_, _, err := registry.Resolve(context.Background(), "https://www.instagram.com/someuser/")
+	require.ErrorIs(t, err, downloaders.ErrNotMediaURL)
+}
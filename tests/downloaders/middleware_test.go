@@ -0,0 +1,55 @@
+package downloaders
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func tracing(trace *[]string, name string) downloaders.Middleware {
+	return func(next downloaders.IDownloader) downloaders.IDownloader {
+		return downloaders.DownloaderFunc(func(ctx context.Context, url string) (*downloaders.Media, error) {
+			*trace = append(*trace, name)
+
+			return next.Download(ctx, url)
+		})
+	}
+}
+
+func TestChainOrder(t *testing.T) {
+	var trace []string
+
+	d := downloaders.Chain(
+		downloaders.DownloaderFunc(func(context.Context, string) (*downloaders.Media, error) {
+			trace = append(trace, "downloader")
+
+			return &downloaders.Media{}, nil
+		}),
+		tracing(&trace, "outer"),
+		tracing(&trace, "inner"),
+	)
+
+	_, err := d.Download(context.Background(), "url")
+	require.NoError(t, err)
+	require.Equal(t, []string{"outer", "inner", "downloader"}, trace)
+}
+
+func TestPipeline(t *testing.T) {
+	utils.InitLogger("error")
+
+	opts := downloaders.MiddlewareOptions{Platform: "test", Timeout: time.Second, CacheTTL: time.Minute}
+
+	d, err := downloaders.Pipeline(stubDownloader("test"), []string{"logging", "cache", "metrics", "timeout"}, opts)
+	require.NoError(t, err)
+
+	media, err := d.Download(context.Background(), "url")
+	require.NoError(t, err)
+	require.NotNil(t, media)
+
+	_, err = downloaders.Pipeline(stubDownloader("test"), []string{"unknown"}, opts)
+	require.Error(t, err)
+}
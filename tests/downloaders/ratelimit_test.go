@@ -0,0 +1,71 @@
+package downloaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterQueuesWithinMaxWait(t *testing.T) {
+	limiter := downloaders.NewRateLimiter(20, 1, time.Second)
+
+	start := time.Now()
+
+	for range 3 {
+		require.NoError(t, limiter.Wait(context.Background()))
+	}
+
+	// Первый запрос проходит сразу, два следующих ждут по 50мс
+	require.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestRateLimiterRejectsLongWait(t *testing.T) {
+	limiter := downloaders.NewRateLimiter(1, 1, 100*time.Millisecond)
+
+	require.NoError(t, limiter.Wait(context.Background()))
+	require.ErrorIs(t, limiter.Wait(context.Background()), downloaders.ErrRateLimited)
+}
+
+func TestRateLimiterRejectsPastDeadline(t *testing.T) {
+	limiter := downloaders.NewRateLimiter(1, 1, time.Minute)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	require.NoError(t, limiter.Wait(ctx))
+	require.ErrorIs(t, limiter.Wait(ctx), downloaders.ErrRateLimited)
+}
+
+func TestRateLimitedTransport(t *testing.T) {
+	utils.InitLogger("error")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	transport := downloaders.NewRateLimitedTransport(nil)
+	transport.Limit("127.0.0.1", downloaders.NewRateLimiter(1, 1, 0))
+	client := http.Client{Transport: transport}
+
+	get := func() error {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		resp, err := client.Do(req)
+		if err == nil {
+			require.NoError(t, resp.Body.Close())
+		}
+
+		return err
+	}
+
+	require.NoError(t, get())
+	require.ErrorIs(t, get(), downloaders.ErrRateLimited)
+}
@@ -0,0 +1,91 @@
+package downloaders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/downloaders/instagram"
+	tiktok "github.com/StounhandJ/shorts_forward/internal/downloaders/tik_tok"
+	"github.com/StounhandJ/shorts_forward/internal/downloaders/youtube"
+	"github.com/stretchr/testify/require"
+)
+
+type stubDownloader string
+
+func (stubDownloader) Download(context.Context, string) (*downloaders.Media, error) {
+	return &downloaders.Media{}, nil
+}
+
+func newRegistry() *downloaders.Registry {
+	registry := downloaders.NewRegistry(nil)
+	registry.Register(youtube.Platform, stubDownloader("youtube"))
+	registry.Register(instagram.Platform, stubDownloader("instagram"))
+	registry.Register(tiktok.Platform, stubDownloader("tiktok"))
+
+	return registry
+}
+
+func TestRegistryResolve(t *testing.T) {
+	registry := newRegistry()
+
+	tests := []struct {
+		url  string
+		link downloaders.Link
+	}{
+		{
+			url:  "https://www.youtube.com/shorts/dQw4w9WgXcQ?feature=share",
+			link: downloaders.Link{Platform: "youtube", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", MediaID: "dQw4w9WgXcQ"},
+		},
+		{
+			url:  "https://youtube.com/watch?v=dQw4w9WgXcQ&t=10",
+			link: downloaders.Link{Platform: "youtube", URL: "https://www.youtube.com/watch?v=dQw4w9WgXcQ", MediaID: "dQw4w9WgXcQ"},
+		},
+		{
+			url:  "https://www.instagram.com/reels/DAbCdEfGhIj/?igsh=abc",
+			link: downloaders.Link{Platform: "instagram", URL: "https://www.instagram.com/reel/DAbCdEfGhIj/", MediaID: "DAbCdEfGhIj"},
+		},
+		{
+			url:  "https://www.instagram.com/p/DAbCdEfGhIj",
+			link: downloaders.Link{Platform: "instagram", URL: "https://www.instagram.com/p/DAbCdEfGhIj/", MediaID: "DAbCdEfGhIj"},
+		},
+		{
+			url:  "https://www.tiktok.com/@user/video/7345678901234567890?is_from_webapp=1",
+			link: downloaders.Link{Platform: "tiktok", URL: "https://www.tiktok.com/@/video/7345678901234567890", MediaID: "7345678901234567890"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			downloader, link, err := registry.Resolve(context.Background(), tt.url)
+			require.NoError(t, err)
+			require.Equal(t, stubDownloader(tt.link.Platform), downloader)
+			require.Equal(t, tt.link, link)
+		})
+	}
+}
+
+func TestRegistryResolveErrors(t *testing.T) {
+	registry := newRegistry()
+
+	_, _, err := registry.Resolve(context.Background(), "https://example.com/video/1")
+	require.ErrorIs(t, err, downloaders.ErrUnsupportedURL)
+
+	_, _, err = registry.Resolve(context.Background(), "https://notyoutube.com/watch?v=dQw4w9WgXcQ")
+	require.ErrorIs(t, err, downloaders.ErrUnsupportedURL)
+
+	_, _, err = registry.Resolve(context.Background(), "https://www.youtube.com/@channel")
+	require.ErrorIs(t, err, downloaders.ErrNotMediaURL)
+
+	_, _, err = registry.Resolve(context.Background(), "https://www.instagram.com/someuser/")
+	require.ErrorIs(t, err, downloaders.ErrNotMediaURL)
+}
+
+func TestLinkID(t *testing.T) {
+	link := downloaders.Link{Platform: "youtube", MediaID: "dQw4w9WgXcQ"}
+	require.Equal(t, "youtube_dQw4w9WgXcQ", link.ID())
+
+	long := downloaders.Link{Platform: "tiktok", MediaID: string(make([]byte, 100))}
+	require.LessOrEqual(t, len(long.ID()), 64)
+	require.Equal(t, long.ID(), long.ID())
+}
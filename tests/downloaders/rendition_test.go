@@ -0,0 +1,38 @@
+package downloaders
+
+import (
+	"context"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/stretchr/testify/require"
+)
+
+// best лучший вариант файла без ограничения размера
+func best(t *testing.T, renditions ...downloaders.Rendition) downloaders.Rendition {
+	t.Helper()
+
+	r, err := downloaders.NewSelector(nil).Select(context.Background(), downloaders.Item{Renditions: renditions}, 0)
+	require.NoError(t, err)
+
+	return r
+}
+
+func TestSelectorQualityOrder(t *testing.T) {
+	require.Equal(t, "hd", best(t,
+		downloaders.Rendition{URL: "wm", Size: 9_000_000, Bitrate: 4_000_000, HasAudio: true, Watermark: true},
+		downloaders.Rendition{URL: "silent", Width: 1920, Height: 1080, Bitrate: 8_000_000},
+		downloaders.Rendition{URL: "sd", Width: 576, Height: 1024, Bitrate: 1_000_000, HasAudio: true},
+		downloaders.Rendition{URL: "hd", Width: 720, Height: 1280, Bitrate: 2_000_000, HasAudio: true},
+		downloaders.Rendition{URL: "", Width: 1080, Height: 1920, HasAudio: true},
+	).URL)
+
+	// Без размеров кадра решает битрейт
+	require.Equal(t, "hdplay", best(t,
+		downloaders.Rendition{URL: "play", Bitrate: 1_000_000, HasAudio: true},
+		downloaders.Rendition{URL: "hdplay", Bitrate: 2_000_000, HasAudio: true},
+	).URL)
+
+	_, err := downloaders.NewSelector(nil).Select(context.Background(), downloaders.Item{}, 0)
+	require.Error(t, err)
+}
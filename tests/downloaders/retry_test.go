@@ -0,0 +1,64 @@
+package downloaders
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// failingDownloader возвращает ошибки по очереди, затем успешный ответ
+func failingDownloader(calls *atomic.Int32, errs ...error) downloaders.IDownloader {
+	return downloaders.DownloaderFunc(func(context.Context, string) (*downloaders.Media, error) {
+		i := int(calls.Add(1)) - 1
+		if i < len(errs) {
+			return nil, errs[i]
+		}
+
+		return &downloaders.Media{}, nil
+	})
+}
+
+func TestRetryTemporary(t *testing.T) {
+	utils.InitLogger("error")
+
+	var calls atomic.Int32
+
+	d := downloaders.Retry("test", 2, time.Millisecond)(
+		failingDownloader(&calls, downloaders.ErrTemporary, downloaders.ErrRateLimited),
+	)
+
+	media, err := d.Download(context.Background(), "url")
+	require.NoError(t, err)
+	require.NotNil(t, media)
+	require.EqualValues(t, 3, calls.Load())
+}
+
+func TestRetrySkipsPermanent(t *testing.T) {
+	var calls atomic.Int32
+
+	d := downloaders.Retry("test", 2, time.Millisecond)(failingDownloader(&calls, downloaders.ErrNotFound))
+
+	_, err := d.Download(context.Background(), "url")
+	require.ErrorIs(t, err, downloaders.ErrNotFound)
+	require.EqualValues(t, 1, calls.Load())
+}
+
+func TestRetryRespectsDeadline(t *testing.T) {
+	var calls atomic.Int32
+
+	d := downloaders.Retry("test", 3, time.Second)(
+		failingDownloader(&calls, downloaders.ErrTemporary, downloaders.ErrTemporary, downloaders.ErrTemporary),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := d.Download(ctx, "url")
+	require.ErrorIs(t, err, downloaders.ErrTemporary)
+	require.EqualValues(t, 1, calls.Load())
+}
@@ -0,0 +1,55 @@
+package downloaders
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectorSelect(t *testing.T) {
+	// /head отдаёт размер на HEAD, /range только на запрос первого байта
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/head" && r.Method == http.MethodHead:
+			w.Header().Set("Content-Length", strconv.Itoa(15<<20))
+		case r.URL.Path == "/range" && r.Header.Get("Range") == "bytes=0-0":
+			w.Header().Set("Content-Range", "bytes 0-0/"+strconv.Itoa(25<<20))
+			w.WriteHeader(http.StatusPartialContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer server.Close()
+
+	selector := downloaders.NewSelector(server.Client())
+	item := downloaders.Item{
+		Type: downloaders.MediaTypeVideo,
+		Renditions: []downloaders.Rendition{
+			{URL: server.URL + "/known", Width: 1080, Height: 1920, Size: 40 << 20, HasAudio: true},
+			{URL: server.URL + "/range", Width: 720, Height: 1280, HasAudio: true},
+			{URL: server.URL + "/head", Width: 576, Height: 1024, HasAudio: true},
+		},
+	}
+
+	r, err := selector.Select(context.Background(), item, 20<<20)
+	require.NoError(t, err)
+	require.Equal(t, server.URL+"/head", r.URL)
+	require.Equal(t, int64(15<<20), r.Size)
+
+	r, err = selector.Select(context.Background(), item, 30<<20)
+	require.NoError(t, err)
+	require.Equal(t, server.URL+"/range", r.URL)
+	require.Equal(t, int64(25<<20), r.Size)
+
+	r, err = selector.Select(context.Background(), item, 0)
+	require.NoError(t, err)
+	require.Equal(t, server.URL+"/known", r.URL)
+
+	_, err = selector.Select(context.Background(), item, 10<<20)
+	require.ErrorIs(t, err, downloaders.ErrTooLarge)
+}
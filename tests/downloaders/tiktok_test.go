@@ -0,0 +1,127 @@
+package downloaders
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	tiktok "github.com/StounhandJ/shorts_forward/internal/downloaders/tik_tok"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/stretchr/testify/require"
+)
+
+// fixtureServer отдаёт testdata/<dir>/<path>.html
+func fixtureServer(t *testing.T, dir string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/"+dir+r.URL.Path+".html")
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestTikTokWebVideo(t *testing.T) {
+	server := fixtureServer(t, "tiktok")
+
+	media, err := tiktok.NewWeb(server.Client()).Download(context.Background(), server.URL+"/video")
+	require.NoError(t, err)
+
+	require.Equal(t, "Котик учится плавать #cat", media.Title)
+	require.Equal(t, "catlover", media.Author.Username)
+	require.Equal(t, 56000, media.ViewCount)
+	require.Equal(t, 1200, media.LikeCount)
+	require.Equal(t, int64(1710000000), media.UploadedAt.Unix())
+	require.True(t, media.Music.Original)
+
+	require.Len(t, media.Items, 1)
+	item := media.Items[0]
+	require.Equal(t, downloaders.MediaTypeVideo, item.Type)
+	require.Equal(t, 15, item.Duration)
+	require.Len(t, item.Renditions, 2)
+
+	// DataSize бывает и строкой, и числом
+	require.Equal(t, int64(2343750), item.Renditions[0].Size)
+	require.Equal(t, int64(1185000), item.Renditions[1].Size)
+	require.Equal(t, 1080, item.Renditions[0].Width)
+	require.Equal(t, "h264", item.Renditions[1].Codec)
+
+	// Ссылки CDN со страницы работают только с её cookie
+	require.True(t, item.Renditions[0].Cookies)
+
+	require.Equal(t, "https://v16-webapp-prime.tiktok.com/video/1080.mp4", best(t, item.Renditions...).URL)
+}
+
+func TestTikTokWebPhoto(t *testing.T) {
+	server := fixtureServer(t, "tiktok")
+
+	media, err := tiktok.NewWeb(server.Client()).Download(context.Background(), server.URL+"/photo")
+	require.NoError(t, err)
+	require.True(t, media.IsAlbum())
+	require.Len(t, media.Items, 2)
+	require.Equal(t, downloaders.MediaTypePhoto, media.Items[0].Type)
+	require.Equal(t, "https://p16-sign.tiktokcdn.com/photo2.jpeg", media.Items[1].Renditions[0].URL)
+}
+
+func TestTikTokWebErrors(t *testing.T) {
+	server := fixtureServer(t, "tiktok")
+	d := tiktok.NewWeb(server.Client())
+
+	_, err := d.Download(context.Background(), server.URL+"/not_found")
+	require.ErrorIs(t, err, downloaders.ErrNotFound)
+
+	_, err = d.Download(context.Background(), server.URL+"/captcha")
+	require.Error(t, err)
+	require.NotErrorIs(t, err, downloaders.ErrNotFound)
+
+	_, err = d.Download(context.Background(), server.URL+"/missing")
+	require.ErrorIs(t, err, downloaders.ErrNotFound)
+}
+
+func TestTikTokWebReadLimit(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		reads []*atomic.Int64
+	)
+
+	_, err := tiktok.NewWeb(endlessClient(&mu, &reads)).Download(context.Background(), "https://www.tiktok.com/@user/video/7345678901234567890")
+	require.ErrorContains(t, err, "больше лимита")
+	require.Len(t, reads, 1)
+	require.LessOrEqual(t, reads[0].Load(), int64(8<<20+64<<10))
+}
+
+func TestTikTokParseErrorFailsOver(t *testing.T) {
+	utils.InitLogger("error")
+
+	server := fixtureServer(t, "tiktok")
+	tikwm := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{},
+			Body:       io.NopCloser(strings.NewReader(`{"code":-1,"msg":"Url parsing is failed! Please check url."}`)),
+			Request:    req,
+		}, nil
+	})}
+
+	// Непонятная tikwm ссылка не значит, что ролика нет
+	_, err := tiktok.New(tikwm).Download(context.Background(), server.URL+"/video")
+	require.ErrorIs(t, err, tiktok.ErrParse)
+	require.False(t, downloaders.IsContentError(err))
+
+	failover := downloaders.NewBreakers().Failover("tiktok", []downloaders.Provider{
+		{Name: "tikwm", Downloader: tiktok.New(tikwm)},
+		{Name: "web", Downloader: tiktok.NewWeb(server.Client())},
+	}, 3, time.Minute)
+
+	media, err := failover.Download(context.Background(), server.URL+"/video")
+	require.NoError(t, err)
+	require.Equal(t, "catlover", media.Author.Username)
+}
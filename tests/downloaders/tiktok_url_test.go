@@ -0,0 +1,142 @@
+package downloaders
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	tiktok "github.com/StounhandJ/shorts_forward/internal/downloaders/tik_tok"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// redirects клиент, который отвечает редиректами вместо настоящих коротких ссылок
+func redirects(requests *atomic.Int32, locations map[string]string) *http.Client {
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests.Add(1)
+
+		resp := &http.Response{StatusCode: http.StatusNotFound, Header: http.Header{}, Body: http.NoBody, Request: req}
+		if location, ok := locations[req.URL.String()]; ok {
+			resp.StatusCode = http.StatusMovedPermanently
+			resp.Header.Set("Location", location)
+		}
+
+		return resp, nil
+	})}
+}
+
+func TestTikTokURLs(t *testing.T) {
+	const (
+		videoURL = "https://www.tiktok.com/@/video/7345678901234567890"
+		photoURL = "https://www.tiktok.com/@/photo/7345678901234567891"
+		videoID  = "7345678901234567890"
+		photoID  = "7345678901234567891"
+	)
+
+	var requests atomic.Int32
+
+	registry := downloaders.NewRegistry(redirects(&requests, map[string]string{
+		"https://vm.tiktok.com/ZMabc123/":  "https://www.tiktok.com/@user/video/" + videoID + "?_r=1&_t=ZM-8abc",
+		"https://vt.tiktok.com/ZSphoto1/":  "https://www.tiktok.com/@user/photo/" + photoID + "?_r=1",
+		"https://www.tiktok.com/t/ZTabc1/": "/@user/video/" + videoID,
+		"https://vm.tiktok.com/ZMchain/":   "https://vt.tiktok.com/ZSnext/",
+		"https://vt.tiktok.com/ZSnext/":    "https://www.tiktok.com/@user/video/" + videoID,
+	}))
+	registry.Register(tiktok.Platform, stubDownloader("tiktok"))
+
+	tests := []struct {
+		url     string
+		want    string
+		mediaID string
+	}{
+		{"https://www.tiktok.com/@user/video/7345678901234567890", videoURL, videoID},
+		{"https://www.tiktok.com/@user/video/7345678901234567890?is_from_webapp=1&sender_device=pc", videoURL, videoID},
+		{"https://tiktok.com/@user/video/7345678901234567890/", videoURL, videoID},
+		{"https://m.tiktok.com/@user/video/7345678901234567890", videoURL, videoID},
+		{"https://www.tiktok.com/@user/photo/7345678901234567891", photoURL, photoID},
+		{"https://m.tiktok.com/v/7345678901234567890.html", videoURL, videoID},
+		{"https://www.tiktok.com/embed/v2/7345678901234567890", videoURL, videoID},
+		{"https://vm.tiktok.com/ZMabc123/", videoURL, videoID},
+		{"https://vt.tiktok.com/ZSphoto1/", photoURL, photoID},
+		{"https://www.tiktok.com/t/ZTabc1/", videoURL, videoID},
+		{"https://vm.tiktok.com/ZMchain/", videoURL, videoID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			_, link, err := registry.Resolve(context.Background(), tt.url)
+			require.NoError(t, err)
+			require.Equal(t, downloaders.Link{Platform: "tiktok", URL: tt.want, MediaID: tt.mediaID}, link)
+		})
+	}
+
+	// Раскрытые короткие ссылки запоминаются
+	before := requests.Load()
+	_, _, err := registry.Resolve(context.Background(), "https://vm.tiktok.com/ZMabc123/")
+	require.NoError(t, err)
+	require.Equal(t, before, requests.Load())
+}
+
+// TestTikTokURLSameVideo все формы ссылки на один ролик дают одну каноническую ссылку,
+// иначе кэш и дедупликация считают их разными роликами
+func TestTikTokURLSameVideo(t *testing.T) {
+	const want = "https://www.tiktok.com/@/video/7345678901234567890"
+
+	var requests atomic.Int32
+
+	registry := downloaders.NewRegistry(redirects(&requests, map[string]string{
+		"https://vm.tiktok.com/ZMabc123/": "https://www.tiktok.com/@renamed/video/7345678901234567890?_r=1",
+	}))
+	registry.Register(tiktok.Platform, stubDownloader("tiktok"))
+
+	for _, url := range []string{
+		"https://www.tiktok.com/@user/video/7345678901234567890",
+		"https://www.tiktok.com/@other.name/video/7345678901234567890?lang=en",
+		"https://m.tiktok.com/v/7345678901234567890.html",
+		"https://www.tiktok.com/video/7345678901234567890",
+		"https://www.tiktok.com/embed/7345678901234567890",
+		"https://www.tiktok.com/embed/v2/7345678901234567890",
+		"https://vm.tiktok.com/ZMabc123/",
+	} {
+		_, link, err := registry.Resolve(context.Background(), url)
+		require.NoError(t, err, url)
+		require.Equal(t, want, link.URL, url)
+	}
+}
+
+func TestTikTokURLErrors(t *testing.T) {
+	var requests atomic.Int32
+
+	registry := downloaders.NewRegistry(redirects(&requests, map[string]string{
+		"https://vm.tiktok.com/ZMprofile/": "https://www.tiktok.com/@user",
+		"https://vm.tiktok.com/ZMaway/":    "https://example.com/",
+	}))
+	registry.Register(tiktok.Platform, stubDownloader("tiktok"))
+
+	tests := []struct {
+		url string
+		err error
+	}{
+		{"https://www.tiktok.com/@user", downloaders.ErrProfileURL},
+		{"https://www.tiktok.com/@user/video/notanid", downloaders.ErrNotMediaURL},
+		{"https://www.tiktok.com/music/original-sound-7300000000000000001", downloaders.ErrNotMediaURL},
+		{"https://vm.tiktok.com/ZMprofile/", downloaders.ErrProfileURL},
+		{"https://vm.tiktok.com/ZMaway/", downloaders.ErrNotMediaURL},
+		{"https://vm.tiktok.com/ZMgone/", downloaders.ErrNotFound},
+		{"https://tiktok.example.com/@user/video/7345678901234567890", downloaders.ErrUnsupportedURL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			_, _, err := registry.Resolve(context.Background(), tt.url)
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package downloaders
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders/youtube"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+const youtubeETag = `"dQw4w9WgXcQ-18-1759000000000000"`
+
+// youtubeModified lastModified форматов из testdata/youtube/player.json
+var youtubeModified = time.Unix(1759000000, 0).UTC()
+
+// youtubeProxy загрузчик с фейковым YouTube и подписанный путь к формату itag 18
+func youtubeProxy(t *testing.T, requests *youtubeRequests, adaptive youtube.Adaptive) (func(*fasthttp.RequestCtx), string) {
+	t.Helper()
+
+	downloader := youtube.New(youtubeFixtures(t, requests), "https://bot.example.com", youtubeSigning, adaptive)
+
+	media, err := downloader.Download(context.Background(), "https://youtu.be/dQw4w9WgXcQ")
+	require.NoError(t, err)
+
+	return downloader.Handler, proxyPath(media.Items[0].Renditions[0].URL)
+}
+
+func TestYouTubeProxyConformance(t *testing.T) {
+	utils.InitLogger("error")
+
+	stream := youtubeStream(4096)
+	modified := youtubeModified.Format(http.TimeFormat)
+
+	for _, tc := range []struct {
+		name    string
+		method  string
+		headers []string
+		status  int
+		// body ожидаемое тело, nil - пустое
+		body []byte
+		// contentRange ожидаемый Content-Range, пусто - заголовка нет
+		contentRange string
+		// upstream сколько раз открыт поток на CDN
+		upstream int32
+	}{
+		{name: "весь файл", status: 200, body: stream, upstream: 1},
+		{name: "HEAD", method: "HEAD", status: 200},
+		{name: "HEAD с Range", method: "HEAD", headers: []string{"Range", "bytes=0-99"}, status: 206, contentRange: "bytes 0-99/4096"},
+		{name: "диапазон", headers: []string{"Range", "bytes=0-99"}, status: 206, body: stream[:100], contentRange: "bytes 0-99/4096", upstream: 1},
+		{name: "до конца", headers: []string{"Range", "bytes=4000-"}, status: 206, body: stream[4000:], contentRange: "bytes 4000-4095/4096", upstream: 1},
+		{name: "конец за файлом", headers: []string{"Range", "bytes=4000-9999"}, status: 206, body: stream[4000:], contentRange: "bytes 4000-4095/4096", upstream: 1},
+		{name: "суффикс", headers: []string{"Range", "bytes=-100"}, status: 206, body: stream[3996:], contentRange: "bytes 3996-4095/4096", upstream: 1},
+		{name: "суффикс больше файла", headers: []string{"Range", "bytes=-10000"}, status: 206, body: stream, contentRange: "bytes 0-4095/4096", upstream: 1},
+		{name: "регистр и пробелы", headers: []string{"Range", "Bytes= 10-19 "}, status: 206, body: stream[10:20], contentRange: "bytes 10-19/4096", upstream: 1},
+		{name: "склейка соседних", headers: []string{"Range", "bytes=0-9,10-19"}, status: 206, body: stream[:20], contentRange: "bytes 0-19/4096", upstream: 1},
+		{name: "начало за файлом", headers: []string{"Range", "bytes=4096-"}, status: 416, contentRange: "bytes */4096"},
+		{name: "пустой суффикс", headers: []string{"Range", "bytes=-0"}, status: 416, contentRange: "bytes */4096"},
+		{name: "все диапазоны за файлом", headers: []string{"Range", "bytes=5000-5001, 6000-"}, status: 416, contentRange: "bytes */4096"},
+		{name: "чужие единицы", headers: []string{"Range", "items=0-9"}, status: 200, body: stream, upstream: 1},
+		{name: "конец раньше начала", headers: []string{"Range", "bytes=10-5"}, status: 200, body: stream, upstream: 1},
+		{name: "мусор", headers: []string{"Range", "bytes=abc"}, status: 200, body: stream, upstream: 1},
+		{name: "отрицательное начало", headers: []string{"Range", "bytes=+1-5"}, status: 200, body: stream, upstream: 1},
+		{name: "слишком много диапазонов", headers: []string{"Range", "bytes=" + strings.Repeat("0-0,", 16) + "0-0"}, status: 200, body: stream, upstream: 1},
+		{name: "If-Range с ETag", headers: []string{"Range", "bytes=0-99", "If-Range", youtubeETag}, status: 206, body: stream[:100], contentRange: "bytes 0-99/4096", upstream: 1},
+		{name: "If-Range с датой", headers: []string{"Range", "bytes=0-99", "If-Range", modified}, status: 206, body: stream[:100], contentRange: "bytes 0-99/4096", upstream: 1},
+		{name: "If-Range устарел", headers: []string{"Range", "bytes=0-99", "If-Range", `"old"`}, status: 200, body: stream, upstream: 1},
+		{name: "If-Range со слабым ETag", headers: []string{"Range", "bytes=0-99", "If-Range", "W/" + youtubeETag}, status: 200, body: stream, upstream: 1},
+		{name: "If-Range со старой датой", headers: []string{"Range", "bytes=0-99", "If-Range", youtubeModified.Add(-time.Hour).Format(http.TimeFormat)}, status: 200, body: stream, upstream: 1},
+		{name: "If-Range с устаревшим 416", headers: []string{"Range", "bytes=5000-", "If-Range", `"old"`}, status: 200, body: stream, upstream: 1},
+		{name: "If-None-Match", headers: []string{"If-None-Match", `"old", ` + youtubeETag}, status: 304},
+		{name: "If-None-Match слабый", headers: []string{"If-None-Match", "W/" + youtubeETag}, status: 304},
+		{name: "If-None-Match *", headers: []string{"If-None-Match", "*"}, status: 304},
+		{name: "If-None-Match другой", headers: []string{"If-None-Match", `"old"`, "If-Modified-Since", modified}, status: 200, body: stream, upstream: 1},
+		{name: "If-Modified-Since", headers: []string{"If-Modified-Since", modified}, status: 304},
+		{name: "If-Modified-Since раньше", headers: []string{"If-Modified-Since", youtubeModified.Add(-time.Hour).Format(http.TimeFormat)}, status: 200, body: stream, upstream: 1},
+		{name: "If-Match", headers: []string{"If-Match", youtubeETag, "Range", "bytes=0-9"}, status: 206, body: stream[:10], contentRange: "bytes 0-9/4096", upstream: 1},
+		{name: "If-Match другой", headers: []string{"If-Match", `"old"`}, status: 412},
+		{name: "If-Match слабый", headers: []string{"If-Match", "W/" + youtubeETag}, status: 412},
+		{name: "If-Unmodified-Since раньше", headers: []string{"If-Unmodified-Since", youtubeModified.Add(-time.Hour).Format(http.TimeFormat)}, status: 412},
+		{name: "If-Unmodified-Since", headers: []string{"If-Unmodified-Since", modified}, status: 200, body: stream, upstream: 1},
+		{name: "POST", method: "POST", status: 405},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests youtubeRequests
+
+			handler, uri := youtubeProxy(t, &requests, youtube.Adaptive{})
+
+			resp := proxyRequest(handler, cmp.Or(tc.method, http.MethodGet), uri, tc.headers...)
+			require.Equal(t, tc.status, resp.StatusCode())
+			require.Equal(t, tc.contentRange, string(resp.Header.Peek("Content-Range")))
+			require.Equal(t, tc.upstream, requests.stream.Load(), "открытий потока")
+
+			if tc.status == 405 {
+				require.Equal(t, "GET, HEAD", string(resp.Header.Peek("Allow")))
+
+				return
+			}
+
+			require.Equal(t, youtubeETag, string(resp.Header.Peek("ETag")))
+			require.Equal(t, modified, string(resp.Header.Peek("Last-Modified")))
+			require.Equal(t, "bytes", string(resp.Header.Peek("Accept-Ranges")))
+
+			if tc.body != nil {
+				require.Equal(t, tc.body, resp.Body())
+				require.Equal(t, len(tc.body), resp.Header.ContentLength())
+			} else {
+				require.Empty(t, resp.Body())
+			}
+
+			if tc.method == "HEAD" {
+				want := 4096
+				if tc.contentRange != "" {
+					want = 100
+				}
+
+				require.Equal(t, want, resp.Header.ContentLength())
+			}
+		})
+	}
+}
+
+func TestYouTubeProxyMultipart(t *testing.T) {
+	utils.InitLogger("error")
+
+	var requests youtubeRequests
+
+	handler, uri := youtubeProxy(t, &requests, youtube.Adaptive{})
+	stream := youtubeStream(4096)
+
+	// Пересекающиеся диапазоны склеиваются, части идут по возрастанию
+	resp := proxyGet(handler, uri, "Range", "bytes=100-109, 0-9, 5-14, -6")
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode())
+	require.Empty(t, resp.Header.Peek("Content-Range"))
+	require.Equal(t, len(resp.Body()), resp.Header.ContentLength())
+
+	mediaType, params, err := mime.ParseMediaType(string(resp.Header.ContentType()))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/byteranges", mediaType)
+
+	reader := multipart.NewReader(strings.NewReader(string(resp.Body())), params["boundary"])
+
+	for _, want := range []struct {
+		start, end int
+	}{{0, 14}, {100, 109}, {4090, 4095}} {
+		part, err := reader.NextPart()
+		require.NoError(t, err)
+		require.Equal(t, "video/mp4", part.Header.Get("Content-Type"))
+		require.Equal(t, fmt.Sprintf("bytes %d-%d/4096", want.start, want.end), part.Header.Get("Content-Range"))
+
+		data, err := io.ReadAll(part)
+		require.NoError(t, err)
+		require.Equal(t, stream[want.start:want.end+1], data)
+	}
+
+	_, err = reader.NextPart()
+	require.ErrorIs(t, err, io.EOF)
+
+	// Каждая часть открывается отдельным запросом к CDN
+	require.Equal(t, int32(3), requests.stream.Load())
+
+	// На HEAD тот же размер без обращения к CDN
+	head := proxyRequest(handler, http.MethodHead, uri, "Range", "bytes=100-109, 0-9, 5-14, -6")
+	require.Equal(t, http.StatusPartialContent, head.StatusCode())
+	require.Equal(t, "multipart/byteranges", strings.Split(string(head.Header.ContentType()), ";")[0])
+	require.Greater(t, head.Header.ContentLength(), 0)
+	require.Equal(t, int32(3), requests.stream.Load())
+}
+
+func TestYouTubeProxyAdaptiveHead(t *testing.T) {
+	utils.InitLogger("error")
+
+	var requests youtubeRequests
+
+	downloader := youtube.New(youtubeFixtures(t, &requests), "https://bot.example.com", youtubeSigning, youtube.Adaptive{Enabled: true})
+
+	media, err := downloader.Download(context.Background(), "https://youtu.be/dQw4w9WgXcQ")
+	require.NoError(t, err)
+
+	uri := proxyPath(media.Items[0].Renditions[1].URL)
+
+	// До склейки размер неизвестен, а склеивать ради HEAD незачем
+	head := proxyRequest(downloader.Handler, http.MethodHead, uri)
+	require.Equal(t, http.StatusOK, head.StatusCode())
+	require.Equal(t, "bytes", string(head.Header.Peek("Accept-Ranges")))
+	require.NotEmpty(t, head.Header.Peek("ETag"))
+	require.Equal(t, int32(0), requests.stream.Load())
+
+	etag := string(head.Header.Peek("ETag"))
+
+	// Условный GET с актуальным ETag тоже не склеивает
+	resp := proxyGet(downloader.Handler, uri, "If-None-Match", etag)
+	require.Equal(t, http.StatusNotModified, resp.StatusCode())
+	require.Equal(t, int32(0), requests.stream.Load())
+
+	resp = proxyGet(downloader.Handler, uri)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+
+	size := len(resp.Body())
+
+	head = proxyRequest(downloader.Handler, http.MethodHead, uri, "Range", "bytes=-10")
+	require.Equal(t, http.StatusPartialContent, head.StatusCode())
+	require.Equal(t, strconv.Itoa(10), string(head.Header.Peek("Content-Length")))
+	require.Equal(t, fmt.Sprintf("bytes %d-%d/%d", size-10, size-1, size), string(head.Header.Peek("Content-Range")))
+	require.Equal(t, int32(2), requests.stream.Load())
+}
+
+func TestYouTubeProxyStreamContext(t *testing.T) {
+	utils.InitLogger("error")
+
+	var requests youtubeRequests
+
+	handler, uri := youtubeProxy(t, &requests, youtube.Adaptive{})
+
+	var ctx fasthttp.RequestCtx
+
+	ctx.Request.SetRequestURI(uri)
+	handler(&ctx)
+
+	// Запрос к CDN не привязан к RequestCtx и живёт, пока тело ответа не закрыто
+	streamCtx, ok := requests.streamCtx.Load().(context.Context)
+	require.True(t, ok)
+	require.NoError(t, streamCtx.Err())
+
+	require.NoError(t, ctx.Response.CloseBodyStream())
+	require.ErrorIs(t, streamCtx.Err(), context.Canceled)
+}
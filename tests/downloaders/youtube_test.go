@@ -0,0 +1,400 @@
+package downloaders
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/downloaders/youtube"
+	"github.com/StounhandJ/shorts_forward/internal/mp4"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// youtubeRequests сколько раз фейковый YouTube получил запросы плеера, страницы embed и CDN
+type youtubeRequests struct {
+	player, embed, stream atomic.Int32
+	// streamCtx контекст последнего запроса к CDN
+	streamCtx atomic.Value
+}
+
+// youtubeStream содержимое потока формата: байт i равен i % 251
+func youtubeStream(size int) []byte {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+
+	return data
+}
+
+// youtubeTrack поток адаптивного формата: MP4 с одной дорожкой, дополненный атомом free до size байт
+func youtubeTrack(t *testing.T, handler string, size int) []byte {
+	t.Helper()
+
+	track := mp4.Track{
+		Handler:     handler,
+		Timescale:   44100,
+		SampleEntry: []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 12, 'm', 'p', '4', 'a', 0, 0, 0, 0},
+	}
+
+	if handler == "vide" {
+		track.Timescale, track.Width, track.Height = 15360, 1080<<16, 1920<<16
+		copy(track.SampleEntry[12:], "avc1")
+	}
+
+	for i := range 20 {
+		track.Samples = append(track.Samples, mp4.Sample{
+			Data:     bytes.Repeat([]byte{handler[0], byte(i)}, 10+i),
+			Duration: 1024,
+			Sync:     handler == "soun" || i%10 == 0,
+		})
+	}
+
+	var file bytes.Buffer
+
+	require.NoError(t, mp4.Write(&file, track))
+
+	padding := size - file.Len()
+	require.GreaterOrEqual(t, padding, 8)
+
+	file.Write(binary.BigEndian.AppendUint32(nil, uint32(padding)))
+	file.WriteString("free")
+	file.Write(make([]byte, padding-8))
+
+	return file.Bytes()
+}
+
+// youtubeFixtures клиент, который отвечает за YouTube файлом testdata/youtube/player.json,
+// а за CDN - потоками youtubeStream и youtubeTrack с поддержкой Range
+func youtubeFixtures(t *testing.T, requests *youtubeRequests) *http.Client {
+	t.Helper()
+
+	streams := map[string][]byte{
+		"18":  youtubeStream(4096),
+		"136": youtubeTrack(t, "vide", 4096),
+		"137": youtubeTrack(t, "vide", 8192),
+		"140": youtubeTrack(t, "soun", 2048),
+	}
+
+	return &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody, Request: req}
+		body := func(s string) io.ReadCloser { return io.NopCloser(strings.NewReader(s)) }
+
+		switch {
+		case req.URL.Path == "/youtubei/v1/player":
+			requests.player.Add(1)
+
+			file, err := os.Open("testdata/youtube/player.json")
+			require.NoError(t, err)
+
+			resp.Body = file
+		case strings.HasPrefix(req.URL.Path, "/embed/"):
+			requests.embed.Add(1)
+			resp.Body = body(`<script src="/s/player/abc123/player_ias.vflset/en_US/base.js"></script>`)
+		case strings.HasSuffix(req.URL.Path, "/base.js"):
+			resp.Body = body("var player;")
+		case strings.HasSuffix(req.URL.Hostname(), ".googlevideo.com"):
+			requests.stream.Add(1)
+			requests.streamCtx.Store(req.Context())
+
+			data := streams[req.URL.Query().Get("itag")]
+			if r := req.Header.Get("Range"); r != "" {
+				var start, end int
+
+				_, err := fmt.Sscanf(r, "bytes=%d-%d", &start, &end)
+				require.NoError(t, err)
+
+				resp.StatusCode = http.StatusPartialContent
+				resp.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+				data = data[start : end+1]
+			}
+
+			resp.ContentLength = int64(len(data))
+			resp.Body = io.NopCloser(bytes.NewReader(data))
+		default:
+			resp.Body = body("<html></html>")
+		}
+
+		return resp, nil
+	})}
+}
+
+// youtubeSigning подпись ссылок на прокси в тестах
+var youtubeSigning = youtube.Signing{Secret: "test-secret"}
+
+// proxyPath путь запроса ТГ к прокси по ссылке из Download
+func proxyPath(url string) string {
+	return strings.TrimPrefix(url, "https://bot.example.com")
+}
+
+// proxyGet запрос ТГ к прокси ролика
+func proxyGet(handler func(*fasthttp.RequestCtx), uri string, headers ...string) *fasthttp.Response {
+	return proxyRequest(handler, http.MethodGet, uri, headers...)
+}
+
+func proxyRequest(handler func(*fasthttp.RequestCtx), method, uri string, headers ...string) *fasthttp.Response {
+	var ctx fasthttp.RequestCtx
+
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(uri)
+
+	for i := 0; i+1 < len(headers); i += 2 {
+		ctx.Request.Header.Set(headers[i], headers[i+1])
+	}
+
+	handler(&ctx)
+
+	var resp fasthttp.Response
+
+	ctx.Response.CopyTo(&resp)
+	resp.SetBody(ctx.Response.Body())
+
+	return &resp
+}
+
+func TestYouTubeResolutionShared(t *testing.T) {
+	utils.InitLogger("error")
+
+	var requests youtubeRequests
+
+	downloader := youtube.New(youtubeFixtures(t, &requests), "https://bot.example.com", youtubeSigning, youtube.Adaptive{})
+
+	media, err := downloader.Download(context.Background(), "https://youtu.be/dQw4w9WgXcQ?si=abc")
+	require.NoError(t, err)
+	require.Equal(t, "Тестовый шортс", media.Title)
+	require.Equal(t, 15, media.Items[0].Duration)
+	require.Len(t, media.Items[0].Renditions, 1)
+	require.True(t, strings.HasPrefix(media.Items[0].Renditions[0].URL, "https://bot.example.com/video?id=dQw4w9WgXcQ&itag=18&exp="))
+
+	uri := proxyPath(media.Items[0].Renditions[0].URL)
+	stream := youtubeStream(4096)
+
+	// ТГ сначала забирает начало файла, затем остаток
+	resp := proxyGet(downloader.Handler, uri, "Range", "bytes=0-1023")
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode())
+	require.Equal(t, "bytes 0-1023/4096", string(resp.Header.Peek("Content-Range")))
+	require.Equal(t, stream[:1024], resp.Body())
+
+	resp = proxyGet(downloader.Handler, uri, "Range", "bytes=1024-")
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode())
+	require.Equal(t, stream[1024:], resp.Body())
+
+	resp = proxyGet(downloader.Handler, uri)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	require.Equal(t, strconv.Itoa(len(stream)), string(resp.Header.Peek("Content-Length")))
+	require.Equal(t, stream, resp.Body())
+
+	// Плеер и страница embed запрошены по разу на все запросы
+	require.Equal(t, int32(1), requests.player.Load())
+	require.Equal(t, int32(1), requests.embed.Load())
+	require.Equal(t, int32(3), requests.stream.Load())
+}
+
+func TestYouTubeAdaptive(t *testing.T) {
+	utils.InitLogger("error")
+
+	for _, tc := range []struct {
+		name     string
+		adaptive youtube.Adaptive
+		url      string
+	}{
+		{"лучшее качество", youtube.Adaptive{Enabled: true}, "https://bot.example.com/video?id=dQw4w9WgXcQ&itag=137&audio=140"},
+		{"ограничение качества", youtube.Adaptive{Enabled: true, MaxHeight: 720}, "https://bot.example.com/video?id=dQw4w9WgXcQ&itag=136&audio=140"},
+		{"ограничение размера", youtube.Adaptive{Enabled: true, MaxSize: 8192}, "https://bot.example.com/video?id=dQw4w9WgXcQ&itag=136&audio=140"},
+		{"слишком большой", youtube.Adaptive{Enabled: true, MaxSize: 4096}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests youtubeRequests
+
+			downloader := youtube.New(youtubeFixtures(t, &requests), "https://bot.example.com", youtubeSigning, tc.adaptive)
+
+			media, err := downloader.Download(context.Background(), "https://youtube.com/shorts/dQw4w9WgXcQ")
+			require.NoError(t, err)
+
+			renditions := media.Items[0].Renditions
+			if tc.url == "" {
+				require.Len(t, renditions, 1)
+
+				return
+			}
+
+			require.Len(t, renditions, 2)
+			require.True(t, strings.HasPrefix(renditions[1].URL, tc.url+"&exp="), renditions[1].URL)
+			require.True(t, renditions[1].HasAudio)
+			require.Contains(t, renditions[1].Codec, "mp4a.40.2")
+		})
+	}
+}
+
+func TestYouTubeAdaptiveProxy(t *testing.T) {
+	utils.InitLogger("error")
+
+	var requests youtubeRequests
+
+	downloader := youtube.New(youtubeFixtures(t, &requests), "https://bot.example.com", youtubeSigning, youtube.Adaptive{Enabled: true})
+
+	media, err := downloader.Download(context.Background(), "https://youtube.com/shorts/dQw4w9WgXcQ")
+	require.NoError(t, err)
+
+	uri := proxyPath(media.Items[0].Renditions[1].URL)
+
+	resp := proxyGet(downloader.Handler, uri)
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+
+	file := bytes.Clone(resp.Body())
+
+	tracks, err := mp4.ReadTracks(file)
+	require.NoError(t, err)
+	require.Len(t, tracks, 2)
+
+	for i, stream := range [][]byte{youtubeTrack(t, "vide", 8192), youtubeTrack(t, "soun", 2048)} {
+		source, err := mp4.ReadTracks(stream)
+		require.NoError(t, err)
+		require.Equal(t, source[0].Handler, tracks[i].Handler)
+		require.Equal(t, source[0].Samples, tracks[i].Samples)
+	}
+
+	// Остаток файла ТГ забирает из уже склеенного ролика
+	resp = proxyGet(downloader.Handler, uri, "Range", "bytes=100-")
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode())
+	require.Equal(t, fmt.Sprintf("bytes 100-%d/%d", len(file)-1, len(file)), string(resp.Header.Peek("Content-Range")))
+	require.Equal(t, file[100:], resp.Body())
+
+	resp = proxyGet(downloader.Handler, uri, "Range", fmt.Sprintf("bytes=%d-", len(file)))
+	require.Equal(t, http.StatusRequestedRangeNotSatisfiable, resp.StatusCode())
+
+	require.Equal(t, int32(2), requests.stream.Load())
+	require.Equal(t, int32(1), requests.player.Load())
+}
+
+// signedPath ссылка на прокси, подписанная так же, как это делает Download
+func signedPath(secret, id string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(id + "\n" + strconv.FormatInt(expires, 10)))
+
+	return fmt.Sprintf("/video?id=%s&itag=18&exp=%d&sig=%s", id, expires, base64.RawURLEncoding.EncodeToString(mac.Sum(nil)))
+}
+
+func TestYouTubeProxySignature(t *testing.T) {
+	utils.InitLogger("error")
+
+	var requests youtubeRequests
+
+	downloader := youtube.New(youtubeFixtures(t, &requests), "https://bot.example.com", youtubeSigning, youtube.Adaptive{})
+
+	media, err := downloader.Download(context.Background(), "https://youtu.be/dQw4w9WgXcQ")
+	require.NoError(t, err)
+
+	uri := proxyPath(media.Items[0].Renditions[0].URL)
+	query, err := url.ParseQuery(strings.SplitN(uri, "?", 2)[1])
+	require.NoError(t, err)
+
+	exp, err := strconv.ParseInt(query.Get("exp"), 10, 64)
+	require.NoError(t, err)
+
+	future := time.Now().Add(time.Hour).Unix()
+	past := time.Now().Add(-time.Minute).Unix()
+
+	for name, path := range map[string]string{
+		"без подписи":  "/video?id=dQw4w9WgXcQ&itag=18",
+		"без срока":    "/video?id=dQw4w9WgXcQ&itag=18&sig=" + query.Get("sig"),
+		"чужой ролик":  strings.Replace(uri, "dQw4w9WgXcQ", "aaaaaaaaaaa", 1),
+		"продлённая":   strings.Replace(uri, "exp="+query.Get("exp"), fmt.Sprintf("exp=%d", exp+3600), 1),
+		"испорченная":  strings.Replace(uri, "sig=", "sig=A", 1),
+		"чужой секрет": signedPath("other-secret", "dQw4w9WgXcQ", future),
+		"устаревшая":   signedPath(youtubeSigning.Secret, "dQw4w9WgXcQ", past),
+	} {
+		t.Run(name, func(t *testing.T) {
+			resp := proxyGet(downloader.Handler, path)
+			require.Equal(t, http.StatusForbidden, resp.StatusCode())
+		})
+	}
+
+	// Ни один отклонённый запрос не дошёл до YouTube
+	require.Equal(t, int32(1), requests.player.Load())
+	require.Equal(t, int32(0), requests.stream.Load())
+
+	// Ссылка, подписанная тем же способом, принимается
+	resp := proxyGet(downloader.Handler, signedPath(youtubeSigning.Secret, "dQw4w9WgXcQ", future))
+	require.Equal(t, http.StatusOK, resp.StatusCode())
+	require.Equal(t, youtubeStream(4096), resp.Body())
+}
+
+func TestYouTubeProxyNoSecret(t *testing.T) {
+	utils.InitLogger("error")
+
+	var requests youtubeRequests
+
+	signing := youtube.Signing{}
+	require.ErrorIs(t, signing.Validate(), youtube.ErrNoSecret)
+
+	downloader := youtube.New(youtubeFixtures(t, &requests), "https://bot.example.com", signing, youtube.Adaptive{})
+
+	// Без секрета ссылки не выдаются
+	_, err := downloader.Download(context.Background(), "https://youtu.be/dQw4w9WgXcQ")
+	require.ErrorIs(t, err, youtube.ErrNoSecret)
+
+	// и не принимаются, даже подписанные пустым ключом
+	resp := proxyGet(downloader.Handler, signedPath("", "dQw4w9WgXcQ", time.Now().Add(time.Hour).Unix()))
+	require.Equal(t, http.StatusForbidden, resp.StatusCode())
+
+	require.Equal(t, int32(0), requests.player.Load())
+	require.Equal(t, int32(0), requests.stream.Load())
+}
+
+func TestYouTubePlayabilityErrors(t *testing.T) {
+	utils.InitLogger("error")
+
+	tests := []struct {
+		name, status, reason string
+		want                 error
+	}{
+		{name: "подтверждение возраста", status: "UNPLAYABLE", reason: "Sign in to confirm your age", want: downloaders.ErrAgeRestricted},
+		{name: "статус проверки возраста", status: "AGE_CHECK_REQUIRED", reason: "", want: downloaders.ErrAgeRestricted},
+		{name: "регион", status: "UNPLAYABLE", reason: "The uploader has not made this video available in your country", want: downloaders.ErrGeoBlocked},
+		{name: "удалено", status: "ERROR", reason: "Video unavailable", want: downloaders.ErrNotFound},
+		// "age" внутри других слов не считается возрастным ограничением
+		{name: "message и page", status: "UNPLAYABLE", reason: "Playback on other websites has been disabled. Watch this page message"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			player := fmt.Sprintf(`{"playabilityStatus": {"status": %q, "reason": %q, "playableInEmbed": true}}`, tt.status, tt.reason)
+
+			client := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{},
+					Body:       io.NopCloser(strings.NewReader(player)),
+					Request:    req,
+				}, nil
+			})}
+
+			_, err := youtube.New(client, "https://bot.example.com", youtubeSigning, youtube.Adaptive{}).
+				Download(context.Background(), "https://youtu.be/dQw4w9WgXcQ")
+			require.Error(t, err)
+
+			if tt.want != nil {
+				require.ErrorIs(t, err, tt.want)
+			} else {
+				require.NotErrorIs(t, err, downloaders.ErrAgeRestricted)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package downloaders
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/downloaders/youtube"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestYouTubeURLs(t *testing.T) {
+	const (
+		id    = "dQw4w9WgXcQ"
+		watch = "https://www.youtube.com/watch?v=" + id
+	)
+
+	registry := newRegistry()
+
+	for _, raw := range []string{
+		"https://youtu.be/dQw4w9WgXcQ",
+		"https://youtu.be/dQw4w9WgXcQ?si=AbCdEfGhIjKlMnOp",
+		"https://www.youtu.be/dQw4w9WgXcQ?t=42",
+		"https://www.youtube.com/watch?v=dQw4w9WgXcQ&feature=share&si=abc",
+		"https://m.youtube.com/watch?v=dQw4w9WgXcQ&pp=ygUE",
+		"https://music.youtube.com/watch?v=dQw4w9WgXcQ&list=RDAMVM",
+		"https://youtube.com/shorts/dQw4w9WgXcQ?si=abc",
+		"https://m.youtube.com/shorts/dQw4w9WgXcQ",
+		"https://www.youtube.com/live/dQw4w9WgXcQ?si=abc",
+		"https://www.youtube.com/embed/dQw4w9WgXcQ?start=10",
+		"https://www.youtube-nocookie.com/embed/dQw4w9WgXcQ",
+		"https://www.youtube.com/v/dQw4w9WgXcQ",
+		"https://www.youtube.com/attribution_link?a=x&u=%2Fwatch%3Fv%3DdQw4w9WgXcQ%26feature%3Dshare",
+	} {
+		t.Run(raw, func(t *testing.T) {
+			_, link, err := registry.Resolve(context.Background(), raw)
+			require.NoError(t, err)
+			require.Equal(t, downloaders.Link{Platform: "youtube", URL: watch, MediaID: id}, link)
+		})
+	}
+}
+
+func TestYouTubeURLErrors(t *testing.T) {
+	registry := newRegistry()
+
+	for _, raw := range []string{
+		"https://youtu.be/",
+		"https://youtu.be/short",
+		"https://www.youtube.com/watch?v=",
+		"https://www.youtube.com/playlist?list=PL123",
+		"https://www.youtube.com/@channel/shorts",
+		"https://music.youtube.com/channel/UC123",
+		"https://www.youtube.com/attribution_link?u=https%3A%2F%2Fexample.com%2Fwatch%3Fv%3DdQw4w9WgXcQ",
+	} {
+		t.Run(raw, func(t *testing.T) {
+			_, _, err := registry.Resolve(context.Background(), raw)
+			require.ErrorIs(t, err, downloaders.ErrNotMediaURL)
+		})
+	}
+}
+
+func TestYouTubeHandlerInvalidID(t *testing.T) {
+	var ctx fasthttp.RequestCtx
+
+	ctx.Request.SetRequestURI("/video?id=bad&itag=18")
+	youtube.New(http.DefaultClient, "", youtube.Signing{}, youtube.Adaptive{}).Handler(&ctx)
+	require.Equal(t, http.StatusBadRequest, ctx.Response.StatusCode())
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+type groupMedia struct {
+	Media   string `json:"media"`
+	Caption string `json:"caption"`
+}
+
+// photos альбом из n фото, элементы из large не влезают ни в один лимит ТГ
+func photos(n int, large ...int) func(cdn string) *downloaders.Media {
+	sizes := make([]int64, n)
+	for i := range sizes {
+		sizes[i] = 1024
+	}
+
+	for _, i := range large {
+		sizes[i] = 60 << 20
+	}
+
+	return sizedPhotos(sizes...)
+}
+
+// sizedPhotos альбом из фото указанных размеров
+func sizedPhotos(sizes ...int64) func(cdn string) *downloaders.Media {
+	return func(cdn string) *downloaders.Media {
+		media := &downloaders.Media{Title: "title"}
+
+		for i, size := range sizes {
+			media.Items = append(media.Items, downloaders.Item{
+				Type:       downloaders.MediaTypePhoto,
+				Renditions: []downloaders.Rendition{{URL: fmt.Sprintf("%s/photo-%d.jpg", cdn, i), MimeType: "image/jpeg", Size: size}},
+			})
+		}
+
+		return media
+	}
+}
+
+// sentGroups группы альбома, отправленные ТГ
+func sentGroups(t *testing.T, api *fakeTelegram) [][]groupMedia {
+	t.Helper()
+
+	var groups [][]groupMedia
+
+	for _, body := range api.called("sendMediaGroup") {
+		var params struct {
+			Media []groupMedia `json:"media"`
+		}
+
+		require.NoError(t, json.Unmarshal(body, &params))
+		groups = append(groups, params.Media)
+	}
+
+	return groups
+}
+
+// sentTexts тексты отправленных сообщений
+func sentTexts(t *testing.T, api *fakeTelegram) []string {
+	t.Helper()
+
+	var texts []string
+
+	for _, body := range api.called("sendMessage") {
+		var params struct {
+			Text string `json:"text"`
+		}
+
+		require.NoError(t, json.Unmarshal(body, &params))
+		texts = append(texts, params.Text)
+	}
+
+	return texts
+}
+
+func TestAlbumGroups(t *testing.T) {
+	tests := []struct {
+		name     string
+		media    func(cdn string) *downloaders.Media
+		groups   []int
+		skipped  []string
+		tooLarge bool
+	}{
+		{name: "два элемента", media: photos(2), groups: []int{2}},
+		{name: "ровно одна группа", media: photos(10), groups: []int{10}},
+		{name: "остаток из одного добирается до двух", media: photos(11), groups: []int{9, 2}},
+		{name: "остаток из двух", media: photos(12), groups: []int{10, 2}},
+		{name: "три группы", media: photos(21), groups: []int{10, 9, 2}},
+		{name: "большой элемент пропускается", media: photos(3, 1), groups: []int{2}, skipped: []string{"/photo-1.jpg"}},
+		{name: "все элементы больше лимита", media: photos(3, 0, 1, 2), tooLarge: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, updates, _ := testBot(t, nil, handlers.StorageChat{}, tt.media)
+
+			updates <- linkMessage()
+
+			total := 0
+			for _, n := range tt.groups {
+				total += n
+			}
+
+			// Ждём, пока уйдёт весь альбом или сообщение об ошибке
+			require.Eventually(t, func() bool {
+				if tt.tooLarge {
+					texts := sentTexts(t, api)
+
+					return len(texts) > 0 && strings.Contains(texts[len(texts)-1], "весит больше")
+				}
+
+				sent := 0
+				for _, group := range sentGroups(t, api) {
+					sent += len(group)
+				}
+
+				return sent == total
+			}, 5*time.Second, 10*time.Millisecond)
+
+			groups := sentGroups(t, api)
+			sizes := make([]int, 0, len(groups))
+
+			for i, group := range groups {
+				sizes = append(sizes, len(group))
+
+				// Подпись только на первом элементе альбома
+				for j, media := range group {
+					if i == 0 && j == 0 {
+						require.Contains(t, media.Caption, "title")
+					} else {
+						require.Empty(t, media.Caption)
+					}
+
+					for _, skipped := range tt.skipped {
+						require.NotContains(t, media.Media, skipped)
+					}
+				}
+			}
+
+			if tt.tooLarge {
+				require.Empty(t, groups)
+			} else {
+				require.Equal(t, tt.groups, sizes)
+			}
+		})
+	}
+}
+
+func TestAlbumItemOverURLLimitIsUploaded(t *testing.T) {
+	// Второе фото больше лимита отправки по ссылке (5 МБ), но влезает в лимит загрузки (10 МБ)
+	api, updates, requested := testBot(t, nil, handlers.StorageChat{}, sizedPhotos(1024, 8<<20, 1024))
+
+	updates <- linkMessage()
+
+	require.Eventually(t, func() bool { return len(api.called("sendPhoto")) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	groups := sentGroups(t, api)
+	require.Len(t, groups, 1)
+	require.Len(t, groups[0], 2)
+	require.NotContains(t, groups[0][0].Media+groups[0][1].Media, "/photo-1.jpg")
+
+	// ТГ получил файл от бота, а не ссылку
+	require.Equal(t, []string{"/photo-1.jpg"}, *requested)
+}
+
+func TestCaptionIsEscapedAndTruncated(t *testing.T) {
+	// Длинное название кириллицей с символами разметки: подпись обрезается по символам и экранируется
+	title := strings.Repeat("Заголовок <b>&", 100)
+
+	tests := []struct {
+		name    string
+		items   int
+		caption func(t *testing.T, api *fakeTelegram) string
+	}{
+		{name: "один файл", items: 1, caption: func(t *testing.T, api *fakeTelegram) string {
+			bodies := api.called("editMessageMedia")
+			if len(bodies) == 0 {
+				return ""
+			}
+
+			var params struct {
+				Media groupMedia `json:"media"`
+			}
+
+			require.NoError(t, json.Unmarshal(bodies[0], &params))
+
+			return params.Media.Caption
+		}},
+		{name: "альбом", items: 2, caption: func(t *testing.T, api *fakeTelegram) string {
+			groups := sentGroups(t, api)
+			if len(groups) == 0 {
+				return ""
+			}
+
+			return groups[0][0].Caption
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api, updates, _ := testBot(t, nil, handlers.StorageChat{}, func(cdn string) *downloaders.Media {
+				media := photos(tt.items)(cdn)
+				media.Title = title
+
+				return media
+			})
+
+			updates <- linkMessage()
+
+			var caption string
+
+			require.Eventually(t, func() bool {
+				caption = tt.caption(t, api)
+
+				return caption != ""
+			}, 5*time.Second, 10*time.Millisecond)
+
+			require.True(t, utf8.ValidString(caption))
+			require.LessOrEqual(t, utf8.RuneCountInString(caption), 1024)
+			require.True(t, strings.HasPrefix(caption, "Заголовок &lt;b&gt;&amp;"))
+			require.NotContains(t, caption, "<b>")
+
+			// Обрезка не разрывает экранированные символы
+			require.NotRegexp(t, `&[a-z]*$`, strings.SplitN(caption, "\n", 2)[0])
+
+			if tt.items > 1 {
+				require.True(t, strings.HasSuffix(caption, fmt.Sprintf(`<a href="%s">Оригинал</a>`, canonical)))
+			}
+		})
+	}
+}
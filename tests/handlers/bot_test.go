@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/cache"
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	tiktok "github.com/StounhandJ/shorts_forward/internal/downloaders/tik_tok"
+	"github.com/StounhandJ/shorts_forward/internal/handlers"
+	"github.com/StounhandJ/shorts_forward/internal/utils"
+	"github.com/mymmrac/telego"
+	th "github.com/mymmrac/telego/telegohandler"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	botToken  = "123456789:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"
+	videoLink = "https://www.tiktok.com/@user/video/7345678901234567890"
+	// canonical ключ кэша публикации
+	canonical = "https://www.tiktok.com/@/video/7345678901234567890"
+)
+
+// fakeTelegram Bot API, который запоминает вызовы, загрузки в хранилище и inline ответы.
+// Inline ответ с устаревшим file_id отклоняется, как это делает ТГ
+type fakeTelegram struct {
+	mu      sync.Mutex
+	failing string
+	calls   []apiCall
+	uploads int
+	answers chan string
+}
+
+type apiCall struct {
+	method string
+	body   []byte
+}
+
+// message ответ ТГ на отправку сообщения
+func message() map[string]any {
+	return map[string]any{"message_id": 1, "date": 0, "chat": map[string]any{"id": 1, "type": "private"}}
+}
+
+func (f *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	method := path.Base(r.URL.Path)
+
+	f.mu.Lock()
+	f.calls = append(f.calls, apiCall{method: method, body: body})
+	failing := f.failing == method
+	f.mu.Unlock()
+
+	if failing {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":false,"error_code":500,"description":"Internal Server Error"}`))
+
+		return
+	}
+
+	var result any = true
+
+	switch method {
+	case "sendMessage", "sendPhoto", "editMessageMedia":
+		result = message()
+	case "sendMediaGroup":
+		var params struct {
+			Media []json.RawMessage `json:"media"`
+		}
+
+		if err := json.Unmarshal(body, &params); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		messages := make([]any, 0, len(params.Media))
+		for range params.Media {
+			messages = append(messages, message())
+		}
+
+		result = messages
+	case "sendVideo":
+		f.mu.Lock()
+		f.uploads++
+		fileID := fmt.Sprintf("file-%d", f.uploads)
+		f.mu.Unlock()
+
+		result = map[string]any{
+			"message_id": 1,
+			"date":       0,
+			"chat":       map[string]any{"id": storageID, "type": "channel"},
+			"video":      map[string]any{"file_id": fileID, "file_unique_id": fileID, "width": 720, "height": 1280, "duration": 1},
+		}
+	case "answerInlineQuery":
+		f.answers <- string(body)
+
+		if bytes.Contains(body, []byte(staleFile)) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: wrong file identifier/HTTP URL specified"}`))
+
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
+
+// called вызовы метода method
+func (f *fakeTelegram) called(method string) [][]byte {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var bodies [][]byte
+
+	for _, call := range f.calls {
+		if call.method == method {
+			bodies = append(bodies, call.body)
+		}
+	}
+
+	return bodies
+}
+
+// fail ТГ отвечает ошибкой на все вызовы метода method
+func (f *fakeTelegram) fail(method string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failing = method
+}
+
+func (f *fakeTelegram) uploaded() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.uploads
+}
+
+type mediaDownloader struct {
+	media *downloaders.Media
+}
+
+func (d mediaDownloader) Download(context.Context, string) (*downloaders.Media, error) {
+	return d.media, nil
+}
+
+// testBot бот, у которого TikTok отдаёт публикацию media(адрес CDN). ТГ и CDN подменены тестовыми серверами
+func testBot(
+	t *testing.T,
+	fileIDs cache.Store[cache.SentMedia],
+	storage handlers.StorageChat,
+	media func(cdn string) *downloaders.Media,
+) (*fakeTelegram, chan<- telego.Update, *[]string) {
+	t.Helper()
+	utils.InitLogger("error")
+
+	var (
+		mu        sync.Mutex
+		requested []string
+	)
+
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requested = append(requested, r.URL.Path)
+		mu.Unlock()
+
+		_, _ = w.Write(bytes.Repeat([]byte{1}, 1024))
+	}))
+	t.Cleanup(cdn.Close)
+
+	api := &fakeTelegram{answers: make(chan string, 4)}
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	bot, err := telego.NewBot(botToken, telego.WithAPIServer(server.URL), telego.WithDiscardLogger())
+	require.NoError(t, err)
+
+	registry := downloaders.NewRegistry(cdn.Client())
+	registry.Register(tiktok.Platform, mediaDownloader{media: media(cdn.URL)})
+
+	handler := handlers.NewHandler(
+		registry,
+		downloaders.NewSelector(cdn.Client()),
+		downloaders.NewFetcher(cdn.Client()),
+		fileIDs,
+		storage,
+		0,
+		nil,
+		nil,
+	)
+
+	updates := make(chan telego.Update)
+
+	bh, err := th.NewBotHandler(bot, updates)
+	require.NoError(t, err)
+
+	handler.SetupRoutes(bh)
+
+	go func() { _ = bh.Start() }()
+
+	t.Cleanup(func() { _ = bh.Stop() })
+
+	return api, updates, &requested
+}
+
+// linkMessage сообщение пользователя со ссылкой на публикацию
+func linkMessage() telego.Update {
+	return telego.Update{Message: &telego.Message{
+		MessageID: 1,
+		From:      &telego.User{ID: 1},
+		Chat:      telego.Chat{ID: 1, Type: telego.ChatTypePrivate},
+		Text:      videoLink,
+	}}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/handlers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSingleFileWithoutLoadMessage(t *testing.T) {
+	api, updates, _ := testBot(t, nil, handlers.StorageChat{}, photos(1))
+
+	// Сообщение "Загрузка...." не отправилось - редактировать нечего, файл уходит новым сообщением
+	api.fail("sendMessage")
+	updates <- linkMessage()
+
+	require.Eventually(t, func() bool { return len(api.called("sendPhoto")) == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.Empty(t, api.called("editMessageMedia"))
+}
+
+func TestCookieRenditionIsUploaded(t *testing.T) {
+	api, updates, requested := testBot(t, nil, handlers.StorageChat{}, func(cdn string) *downloaders.Media {
+		return &downloaders.Media{
+			Title: "title",
+			Items: []downloaders.Item{{
+				Type: downloaders.MediaTypeVideo,
+				Renditions: []downloaders.Rendition{
+					{URL: cdn + smallVideo, MimeType: "video/mp4", Size: 1024, HasAudio: true, Cookies: true},
+				},
+			}},
+		}
+	})
+
+	updates <- linkMessage()
+
+	require.Eventually(t, func() bool { return len(api.called("editMessageMedia")) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	// ТГ не получил ссылку, для которой нужны cookie: файл скачал и загрузил бот
+	require.NotContains(t, string(api.called("editMessageMedia")[0]), smallVideo)
+	require.Equal(t, []string{smallVideo}, *requested)
+}
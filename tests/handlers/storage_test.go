@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/StounhandJ/shorts_forward/internal/cache"
+	"github.com/StounhandJ/shorts_forward/internal/downloaders"
+	"github.com/StounhandJ/shorts_forward/internal/handlers"
+	"github.com/mymmrac/telego"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	storageID  = -1001234567890
+	staleFile  = "stale-file-id"
+	smallVideo = "/small.mp4"
+	bigVideo   = "/big.mp4"
+)
+
+// storageBot бот с чатом-хранилищем до maxSize байт, ТГ и CDN платформы подменены тестовыми серверами
+func storageBot(t *testing.T, fileIDs cache.Store[cache.SentMedia], maxSize int64) (*fakeTelegram, chan<- telego.Update, *[]string) {
+	t.Helper()
+
+	return testBot(t, fileIDs, handlers.StorageChat{ChatID: storageID, MaxSize: maxSize}, func(cdn string) *downloaders.Media {
+		return &downloaders.Media{
+			Title: "title",
+			Items: []downloaders.Item{{
+				Type: downloaders.MediaTypeVideo,
+				Renditions: []downloaders.Rendition{
+					{URL: cdn + bigVideo, MimeType: "video/mp4", Width: 1080, Height: 1920, Size: 30 << 20, HasAudio: true},
+					{URL: cdn + smallVideo, MimeType: "video/mp4", Width: 720, Height: 1280, Size: 1024, HasAudio: true},
+				},
+			}},
+		}
+	})
+}
+
+// inlineQuery отправляет inline запрос и ждёт ответов ТГ на него
+func inlineQuery(t *testing.T, api *fakeTelegram, updates chan<- telego.Update, answers int) []string {
+	t.Helper()
+
+	updates <- telego.Update{InlineQuery: &telego.InlineQuery{ID: "query", From: telego.User{ID: 1}, Query: videoLink}}
+
+	got := make([]string, 0, answers)
+
+	for range answers {
+		select {
+		case answer := <-api.answers:
+			got = append(got, answer)
+		case <-time.After(5 * time.Second):
+			t.Fatal("нет ответа на inline запрос")
+		}
+	}
+
+	return got
+}
+
+func TestStorageUploadSmallerRendition(t *testing.T) {
+	fileIDs := cache.NewLRU[cache.SentMedia](10, 0)
+	api, updates, requested := storageBot(t, fileIDs, 10<<20)
+
+	answers := inlineQuery(t, api, updates, 1)
+	require.Contains(t, answers[0], `"video_file_id":"file-1"`)
+
+	// Лучший вариант больше лимита хранилища, загружен вариант поменьше
+	require.Equal(t, 1, api.uploaded())
+	require.Equal(t, []string{smallVideo}, *requested)
+
+	sent, ok := fileIDs.Get(canonical)
+	require.True(t, ok)
+	require.Equal(t, "file-1", sent.Files[0].FileID)
+}
+
+func TestStorageReusesFileID(t *testing.T) {
+	fileIDs := cache.NewLRU[cache.SentMedia](10, 0)
+	api, updates, _ := storageBot(t, fileIDs, 0)
+
+	inlineQuery(t, api, updates, 1)
+	require.Equal(t, 1, api.uploaded())
+
+	// Повторный запрос отвечается по сохранённому file_id без загрузки
+	answers := inlineQuery(t, api, updates, 1)
+	require.Contains(t, answers[0], `"video_file_id":"file-1"`)
+	require.Equal(t, 1, api.uploaded())
+}
+
+func TestStorageReuploadsStaleFileID(t *testing.T) {
+	fileIDs := cache.NewLRU[cache.SentMedia](10, 0)
+	fileIDs.Set(canonical, cache.SentMedia{Title: "title", Files: []cache.SentFile{{FileID: staleFile}}})
+
+	api, updates, _ := storageBot(t, fileIDs, 0)
+
+	// ТГ отклоняет устаревший file_id, публикация загружается заново
+	answers := inlineQuery(t, api, updates, 2)
+	require.Contains(t, answers[0], staleFile)
+	require.Contains(t, answers[1], `"video_file_id":"file-1"`)
+	require.Equal(t, 1, api.uploaded())
+
+	sent, ok := fileIDs.Get(canonical)
+	require.True(t, ok)
+	require.Equal(t, "file-1", sent.Files[0].FileID)
+}
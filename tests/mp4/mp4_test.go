@@ -0,0 +1,311 @@
+package mp4
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/StounhandJ/shorts_forward/internal/mp4"
+	"github.com/stretchr/testify/require"
+)
+
+func u32(v uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, v)
+}
+
+func atom(typ string, parts ...[]byte) []byte {
+	body := bytes.Join(parts, nil)
+
+	return append(append(u32(uint32(len(body)+8)), typ...), body...)
+}
+
+func fullAtom(typ string, version uint8, flags uint32, parts ...[]byte) []byte {
+	return atom(typ, append([][]byte{u32(uint32(version)<<24 | flags)}, parts...)...)
+}
+
+// testTrack дорожка, из которой собирается фрагментированный файл как у YouTube DASH
+type testTrack struct {
+	handler   string
+	codec     string
+	timescale uint32
+	width     uint32
+	samples   []mp4.Sample
+	// edts атом edit list, nil - без него
+	edts []byte
+}
+
+func sampleEntry(codec string) []byte {
+	return append(append(u32(0), u32(1)...), atom(codec, bytes.Repeat([]byte{7}, 20))...)
+}
+
+// fragmented файл из одной дорожки: moov с mvex и по moof+mdat на каждые perFragment образцов
+func fragmented(track testTrack, perFragment int) []byte {
+	tkhd := bytes.Join([][]byte{
+		make([]byte, 8), u32(1), make([]byte, 4+4+8+8), make([]byte, 36), u32(track.width << 16), u32(track.width << 16),
+	}, nil)
+
+	file := bytes.Join([][]byte{
+		atom("ftyp", []byte("dash"), u32(0), []byte("iso6")),
+		atom("moov",
+			fullAtom("mvhd", 0, 0, make([]byte, 8), u32(1000), make([]byte, 84)),
+			atom("trak",
+				fullAtom("tkhd", 0, 3, tkhd),
+				track.edts,
+				atom("mdia",
+					fullAtom("mdhd", 0, 0, make([]byte, 8), u32(track.timescale), u32(0), u32(0x55C4<<16)),
+					fullAtom("hdlr", 0, 0, u32(0), []byte(track.handler), make([]byte, 12), []byte("test\x00")),
+					atom("minf", atom("stbl",
+						atom("stsd", sampleEntry(track.codec)),
+						fullAtom("stts", 0, 0, u32(0)),
+						fullAtom("stsc", 0, 0, u32(0)),
+						fullAtom("stsz", 0, 0, u32(0), u32(0)),
+						fullAtom("stco", 0, 0, u32(0)),
+					)),
+				),
+			),
+			atom("mvex", fullAtom("trex", 0, 0, u32(1), u32(1), u32(0), u32(0), u32(0))),
+		),
+	}, nil)
+
+	for first := 0; first < len(track.samples); first += perFragment {
+		samples := track.samples[first:min(first+perFragment, len(track.samples))]
+
+		trun := func(dataOffset uint32) []byte {
+			entries := [][]byte{u32(uint32(len(samples))), u32(dataOffset)}
+
+			for _, s := range samples {
+				flags := uint32(0x10000)
+				if s.Sync {
+					flags = 0
+				}
+
+				entries = append(entries, u32(s.Duration), u32(uint32(len(s.Data))), u32(flags), u32(uint32(s.CompositionOffset)))
+			}
+
+			return fullAtom("trun", 1, 0xF01, entries...)
+		}
+
+		moof := func(dataOffset uint32) []byte {
+			return atom("moof",
+				fullAtom("mfhd", 0, 0, u32(uint32(first+1))),
+				atom("traf", fullAtom("tfhd", 0, 0, u32(1)), trun(dataOffset)),
+			)
+		}
+
+		// Данные образцов идут сразу за moof после заголовка mdat
+		header := moof(0)
+		file = append(file, moof(uint32(len(header)+8))...)
+
+		var data [][]byte
+		for _, s := range samples {
+			data = append(data, s.Data)
+		}
+
+		file = append(file, atom("mdat", data...)...)
+	}
+
+	return file
+}
+
+// testVideo 2 секунды видео 30 к/с с ключевым кадром раз в секунду и B-кадрами
+func testVideo() testTrack {
+	track := testTrack{handler: "vide", codec: "avc1", timescale: 15360, width: 1080}
+
+	for i := range 60 {
+		track.samples = append(track.samples, mp4.Sample{
+			Data:              bytes.Repeat([]byte(fmt.Sprintf("v%02d", i)), 10+i%7),
+			Duration:          512,
+			CompositionOffset: 1024 - int32(i%3)*512,
+			Sync:              i%30 == 0,
+		})
+	}
+
+	return track
+}
+
+// testAudio 2 секунды AAC 44.1 кГц
+func testAudio() testTrack {
+	track := testTrack{handler: "soun", codec: "mp4a", timescale: 44100}
+
+	for i := range 87 {
+		track.samples = append(track.samples, mp4.Sample{
+			Data:     bytes.Repeat([]byte(fmt.Sprintf("a%02d", i)), 5+i%3),
+			Duration: 1024,
+			Sync:     true,
+		})
+	}
+
+	return track
+}
+
+func readTrack(t *testing.T, file []byte) mp4.Track {
+	t.Helper()
+
+	tracks, err := mp4.ReadTracks(file)
+	require.NoError(t, err)
+	require.Len(t, tracks, 1)
+
+	return tracks[0]
+}
+
+// topLevel типы атомов верхнего уровня
+func topLevel(t *testing.T, file []byte) []string {
+	t.Helper()
+
+	var types []string
+
+	for pos := 0; pos < len(file); {
+		require.GreaterOrEqual(t, len(file)-pos, 8)
+
+		size := int(binary.BigEndian.Uint32(file[pos:]))
+		require.GreaterOrEqual(t, size, 8)
+
+		types = append(types, string(file[pos+4:pos+8]))
+		pos += size
+	}
+
+	return types
+}
+
+func TestRemuxFragmented(t *testing.T) {
+	video, audio := testVideo(), testAudio()
+
+	videoTrack := readTrack(t, fragmented(video, 25))
+	audioTrack := readTrack(t, fragmented(audio, 40))
+
+	require.Equal(t, "vide", videoTrack.Handler)
+	require.Equal(t, uint32(1080<<16), videoTrack.Width)
+	require.Equal(t, video.samples, videoTrack.Samples)
+	require.Equal(t, audio.samples, audioTrack.Samples)
+
+	var out bytes.Buffer
+
+	require.NoError(t, mp4.Write(&out, videoTrack, audioTrack))
+
+	// moov перед данными, чтобы ТГ мог показывать ролик до полной загрузки
+	require.Equal(t, []string{"ftyp", "moov", "mdat"}, topLevel(t, out.Bytes()))
+
+	tracks, err := mp4.ReadTracks(out.Bytes())
+	require.NoError(t, err)
+	require.Len(t, tracks, 2)
+
+	for i, want := range []mp4.Track{videoTrack, audioTrack} {
+		got := tracks[i]
+		require.Equal(t, want.Handler, got.Handler)
+		require.Equal(t, want.Timescale, got.Timescale)
+		require.Equal(t, want.Width, got.Width)
+		require.Equal(t, want.SampleEntry, got.SampleEntry)
+		require.Equal(t, want.Samples, got.Samples)
+	}
+
+	// Повторная запись прочитанного результата даёт тот же файл
+	var again bytes.Buffer
+
+	require.NoError(t, mp4.Write(&again, tracks...))
+	require.Equal(t, out.Bytes(), again.Bytes())
+}
+
+// elst edit list из записей duration (в Timescale mvhd), mediaTime, rate (16.16)
+func elst(entries ...[3]int64) []byte {
+	parts := [][]byte{u32(uint32(len(entries)))}
+
+	for _, e := range entries {
+		parts = append(parts, u32(uint32(e[0])), u32(uint32(int32(e[1]))), u32(uint32(e[2])))
+	}
+
+	return atom("edts", fullAtom("elst", 0, 0, parts...))
+}
+
+func TestEditList(t *testing.T) {
+	// Звук AAC начинается после 2048 отсчётов вступления, а показ - после задержки в 500 мс
+	audio := testAudio()
+	audio.edts = elst([3]int64{500, -1, 1 << 16}, [3]int64{2000, 2048, 1 << 16})
+
+	audioTrack := readTrack(t, fragmented(audio, 40))
+	require.True(t, audioTrack.Edited)
+	require.Equal(t, uint64(2048), audioTrack.Start)
+	require.Equal(t, uint64(22050), audioTrack.Delay)
+
+	// Edit list с нулевым началом: задержку B-кадров исходный файл оставил, её не убираем
+	video := testVideo()
+	video.edts = elst([3]int64{2000, 0, 1 << 16})
+
+	videoTrack := readTrack(t, fragmented(video, 25))
+	require.True(t, videoTrack.Edited)
+	require.Zero(t, videoTrack.Start)
+
+	var out bytes.Buffer
+
+	require.NoError(t, mp4.Write(&out, videoTrack, audioTrack))
+
+	tracks, err := mp4.ReadTracks(out.Bytes())
+	require.NoError(t, err)
+	require.Len(t, tracks, 2)
+
+	require.Zero(t, tracks[0].Start)
+	require.Equal(t, uint64(2048), tracks[1].Start)
+	require.Equal(t, uint64(22050), tracks[1].Delay)
+
+	// Без edit list задержка первого кадра убирается
+	out.Reset()
+	require.NoError(t, mp4.Write(&out, readTrack(t, fragmented(testVideo(), 25))))
+
+	tracks, err = mp4.ReadTracks(out.Bytes())
+	require.NoError(t, err)
+	require.True(t, tracks[0].Edited)
+	require.Equal(t, uint64(1024), tracks[0].Start)
+}
+
+func TestEditListUnsupported(t *testing.T) {
+	for name, edts := range map[string][]byte{
+		"две записи":       elst([3]int64{1000, 0, 1 << 16}, [3]int64{1000, 2048, 1 << 16}),
+		"скорость":         elst([3]int64{2000, 0, 2 << 16}),
+		"пустая в конце":   elst([3]int64{2000, 0, 1 << 16}, [3]int64{500, -1, 1 << 16}),
+		"пауза в середине": elst([3]int64{1000, 0, 0}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			audio := testAudio()
+			audio.edts = edts
+
+			_, err := mp4.ReadTracks(fragmented(audio, 40))
+			require.ErrorIs(t, err, mp4.ErrUnsupported)
+		})
+	}
+}
+
+func TestReadTracksInvalid(t *testing.T) {
+	file := fragmented(testVideo(), 30)
+
+	for name, data := range map[string][]byte{
+		"пусто":         nil,
+		"мусор":         []byte("not an mp4 file at all"),
+		"обрезан":       file[:len(file)-10],
+		"без moov":      atom("mdat", []byte("data")),
+		"огромный атом": append(u32(1<<30), "moov"...),
+		"таблица stts":  progressiveWithStts(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := mp4.ReadTracks(data)
+			require.ErrorIs(t, err, mp4.ErrInvalid)
+		})
+	}
+
+	require.ErrorIs(t, mp4.Write(&bytes.Buffer{}), mp4.ErrInvalid)
+}
+
+// progressiveWithStts обычный файл, в stts которого заявлено 4 миллиарда записей
+func progressiveWithStts() []byte {
+	return atom("moov", atom("trak",
+		fullAtom("tkhd", 0, 3, make([]byte, 80)),
+		atom("mdia",
+			fullAtom("mdhd", 0, 0, make([]byte, 8), u32(1000), u32(0), u32(0)),
+			fullAtom("hdlr", 0, 0, u32(0), []byte("vide"), make([]byte, 12)),
+			atom("minf", atom("stbl",
+				atom("stsd", sampleEntry("avc1")),
+				fullAtom("stsz", 0, 0, u32(0), u32(1), u32(4)),
+				fullAtom("stts", 0, 0, u32(0xFFFFFFFF)),
+			)),
+		),
+	))
+}